@@ -6,6 +6,14 @@ import (
 	"github.com/nicklaw5/helix/v2"
 )
 
+// event types not yet known to helix
+const (
+	EventSubTypeChannelVIPAdd    = "channel.vip.add"
+	EventSubTypeChannelVIPRemove = "channel.vip.remove"
+
+	EventSubTypeChannelPointsAutomaticRewardRedemptionAdd = "channel.channel_points_automatic_reward_redemption.add"
+)
+
 func EventSetup(
 	client *helix.Client,
 	webSocketSessionID string,
@@ -44,6 +52,14 @@ func EventSetup(
 		},
 		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
 	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelCheer,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
 	subEvent(client, &helix.EventSubSubscription{
 		Type:    helix.EventSubTypeChannelRaid,
 		Version: "1",
@@ -52,6 +68,14 @@ func EventSetup(
 		},
 		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
 	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelRaid,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			FromBroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
 	subEvent(client, &helix.EventSubSubscription{
 		Type:    helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd,
 		Version: "1",
@@ -60,6 +84,82 @@ func EventSetup(
 		},
 		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
 	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelPointsCustomRewardRedemptionUpdate,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelPointsCustomRewardAdd,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelPointsCustomRewardUpdate,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelPointsCustomRewardRemove,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelChatNotification,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+			UserID:            broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelChatClear,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+			UserID:            broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelChatClearUserMessages,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+			UserID:            broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelChatMessageDelete,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+			UserID:            broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    EventSubTypeChannelPointsAutomaticRewardRedemptionAdd,
+		Version: "2",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
 	subEvent(client, &helix.EventSubSubscription{
 		Type:    helix.EventSubShoutoutCreate,
 		Version: "1",
@@ -69,6 +169,15 @@ func EventSetup(
 		},
 		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
 	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubShoutoutReceive,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+			ModeratorUserID:   broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
 	subEvent(client, &helix.EventSubSubscription{
 		Type:    helix.EventSubTypeCharityDonation,
 		Version: "1",
@@ -77,6 +186,78 @@ func EventSetup(
 		},
 		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
 	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeCharityStart,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeCharityProgress,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeCharityStop,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelBan,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelUnban,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeModeratorAdd,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeModeratorRemove,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    EventSubTypeChannelVIPAdd,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    EventSubTypeChannelVIPRemove,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
 	subEvent(client, &helix.EventSubSubscription{
 		Type:    helix.EventSubTypeChannelPollBegin,
 		Version: "1",
@@ -133,6 +314,46 @@ func EventSetup(
 		},
 		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
 	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeStreamOnline,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeStreamOffline,
+		Version: "1",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+	subEvent(client, &helix.EventSubSubscription{
+		Type:    helix.EventSubTypeChannelUpdate,
+		Version: "2",
+		Condition: helix.EventSubCondition{
+			BroadcasterUserID: broadcasterUserID,
+		},
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
+}
+
+// CustomEventSetup subscribes a single event type that is not part of the default EventSetup.
+func CustomEventSetup(
+	client *helix.Client,
+	webSocketSessionID string,
+	eventType string,
+	version string,
+	condition helix.EventSubCondition,
+) {
+	subEvent(client, &helix.EventSubSubscription{
+		Type:      eventType,
+		Version:   version,
+		Condition: condition,
+		Transport: helix.EventSubTransport{Method: "websocket", SessionID: webSocketSessionID},
+	})
 }
 
 func subEvent(client *helix.Client, eventPayload *helix.EventSubSubscription) {
@@ -0,0 +1,440 @@
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+var ErrUnknownEventType = errors.New("unknown event type")
+
+type (
+	UserRef struct {
+		UserID    string `json:"user_id"`
+		UserLogin string `json:"user_login"`
+		UserName  string `json:"user_name"`
+	}
+	ModeratorRef struct {
+		ModeratorUserID    string `json:"moderator_user_id"`
+		ModeratorUserLogin string `json:"moderator_user_login"`
+		ModeratorUserName  string `json:"moderator_user_name"`
+	}
+	BroadcasterRef struct {
+		BroadcasterUserID    string `json:"broadcaster_user_id"`
+		BroadcasterUserLogin string `json:"broadcaster_user_login"`
+		BroadcasterUserName  string `json:"broadcaster_user_name"`
+	}
+	Amount struct {
+		Value         int    `json:"value"`
+		DecimalPlaces int    `json:"decimal_places"`
+		Currency      string `json:"currency"`
+	}
+	MessageEmote struct {
+		ID    string `json:"id"`
+		Begin int    `json:"begin"`
+		End   int    `json:"end"`
+	}
+	FragmentCheermote struct {
+		Prefix string `json:"prefix"`
+		Bits   int    `json:"bits"`
+		Tier   int    `json:"tier"`
+	}
+	FragmentEmote struct {
+		ID         string `json:"id"`
+		EmoteSetID string `json:"emote_set_id"`
+	}
+	FragmentMention struct {
+		UserID    string `json:"user_id"`
+		UserLogin string `json:"user_login"`
+		UserName  string `json:"user_name"`
+	}
+	MessageFragment struct {
+		Type      string             `json:"type"`
+		Text      string             `json:"text"`
+		Cheermote *FragmentCheermote `json:"cheermote"`
+		Emote     *FragmentEmote     `json:"emote"`
+		Mention   *FragmentMention   `json:"mention"`
+	}
+	PollChoice struct {
+		ID                 string `json:"id"`
+		Title              string `json:"title"`
+		BitsVotes          int    `json:"bits_votes"`
+		ChannelPointsVotes int    `json:"channel_points_votes"`
+		Votes              int    `json:"votes"`
+	}
+	PredictionTopPredictor struct {
+		UserRef
+		ChannelPointsUsed int `json:"channel_points_used"`
+		ChannelPointsWon  int `json:"channel_points_won"`
+	}
+	PredictionOutcome struct {
+		ID            string                   `json:"id"`
+		Title         string                   `json:"title"`
+		Color         string                   `json:"color"`
+		Users         int                      `json:"users"`
+		ChannelPoints int                      `json:"channel_points"`
+		TopPredictors []PredictionTopPredictor `json:"top_predictors"`
+	}
+)
+
+type (
+	ChannelFollowEventV2 struct {
+		UserRef
+		FollowedAt time.Time `json:"followed_at"`
+	}
+	ChannelSubscribeEventV1 struct {
+		UserRef
+		Tier   string `json:"tier"`
+		IsGift bool   `json:"is_gift"`
+	}
+	ChannelSubscriptionMessageEventV1 struct {
+		UserRef
+		Tier    string `json:"tier"`
+		Message struct {
+			Text   string         `json:"text"`
+			Emotes []MessageEmote `json:"emotes"`
+		} `json:"message"`
+		CumulativeMonths int `json:"cumulative_months"`
+		StreakMonths     int `json:"streak_months"`
+		DurationMonths   int `json:"duration_months"`
+	}
+	ChannelSubscriptionGiftEventV1 struct {
+		UserRef
+		Total           int    `json:"total"`
+		Tier            string `json:"tier"`
+		CumulativeTotal int    `json:"cumulative_total"`
+		IsAnonymous     bool   `json:"is_anonymous"`
+	}
+	ChannelCheerEventV1 struct {
+		UserRef
+		IsAnonymous bool   `json:"is_anonymous"`
+		Message     string `json:"message"`
+		Bits        int    `json:"bits"`
+	}
+	ChannelRaidEventV1 struct {
+		FromBroadcasterUserID    string `json:"from_broadcaster_user_id"`
+		FromBroadcasterUserLogin string `json:"from_broadcaster_user_login"`
+		FromBroadcasterUserName  string `json:"from_broadcaster_user_name"`
+		ToBroadcasterUserID      string `json:"to_broadcaster_user_id"`
+		ToBroadcasterUserLogin   string `json:"to_broadcaster_user_login"`
+		ToBroadcasterUserName    string `json:"to_broadcaster_user_name"`
+		Viewers                  int    `json:"viewers"`
+	}
+	ChannelPointsRedemptionEventV1 struct {
+		ID string `json:"id"`
+		UserRef
+		UserInput string `json:"user_input"`
+		Status    string `json:"status"`
+		Reward    struct {
+			ID     string `json:"id"`
+			Title  string `json:"title"`
+			Cost   int    `json:"cost"`
+			Prompt string `json:"prompt"`
+		} `json:"reward"`
+		RedeemedAt time.Time `json:"redeemed_at"`
+	}
+	ChannelPointsRewardEventV1 struct {
+		ID                  string `json:"id"`
+		Title               string `json:"title"`
+		Prompt              string `json:"prompt"`
+		Cost                int    `json:"cost"`
+		BackgroundColor     string `json:"background_color"`
+		IsEnabled           bool   `json:"is_enabled"`
+		IsPaused            bool   `json:"is_paused"`
+		IsInStock           bool   `json:"is_in_stock"`
+		IsUserInputRequired bool   `json:"is_user_input_required"`
+	}
+	ChannelPointsAutomaticRedemptionEventV2 struct {
+		ID string `json:"id"`
+		UserRef
+		Reward struct {
+			Type          string `json:"type"`
+			ChannelPoints int    `json:"channel_points"`
+			Emote         *struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"emote"`
+		} `json:"reward"`
+		Message *struct {
+			Text      string            `json:"text"`
+			Fragments []MessageFragment `json:"fragments"`
+		} `json:"message"`
+		RedeemedAt time.Time `json:"redeemed_at"`
+	}
+	ShoutoutCreateEventV1 struct {
+		ModeratorRef
+		ToBroadcasterUserID    string    `json:"to_broadcaster_user_id"`
+		ToBroadcasterUserLogin string    `json:"to_broadcaster_user_login"`
+		ToBroadcasterUserName  string    `json:"to_broadcaster_user_name"`
+		ViewerCount            int       `json:"viewer_count"`
+		StartedAt              time.Time `json:"started_at"`
+	}
+	ShoutoutReceiveEventV1 struct {
+		FromBroadcasterUserID    string    `json:"from_broadcaster_user_id"`
+		FromBroadcasterUserLogin string    `json:"from_broadcaster_user_login"`
+		FromBroadcasterUserName  string    `json:"from_broadcaster_user_name"`
+		ViewerCount              int       `json:"viewer_count"`
+		StartedAt                time.Time `json:"started_at"`
+	}
+	CharityDonationEventV1 struct {
+		ID         string `json:"id"`
+		CampaignID string `json:"campaign_id"`
+		UserRef
+		CharityName string `json:"charity_name"`
+		Amount      Amount `json:"amount"`
+	}
+	CharityCampaignEventV1 struct {
+		ID                 string    `json:"id"`
+		CharityName        string    `json:"charity_name"`
+		CharityDescription string    `json:"charity_description"`
+		CharityLogo        string    `json:"charity_logo"`
+		CharityWebsite     string    `json:"charity_website"`
+		CurrentAmount      Amount    `json:"current_amount"`
+		TargetAmount       Amount    `json:"target_amount"`
+		StartedAt          time.Time `json:"started_at"`
+		StoppedAt          time.Time `json:"stopped_at"`
+	}
+	ChannelPollEventV1 struct {
+		ID        string       `json:"id"`
+		Title     string       `json:"title"`
+		Choices   []PollChoice `json:"choices"`
+		Status    string       `json:"status"`
+		StartedAt time.Time    `json:"started_at"`
+		EndsAt    time.Time    `json:"ends_at"`
+		EndedAt   time.Time    `json:"ended_at"`
+	}
+	ChannelPredictionEventV1 struct {
+		ID               string              `json:"id"`
+		Title            string              `json:"title"`
+		WinningOutcomeID string              `json:"winning_outcome_id"`
+		Outcomes         []PredictionOutcome `json:"outcomes"`
+		Status           string              `json:"status"`
+		StartedAt        time.Time           `json:"started_at"`
+		LocksAt          time.Time           `json:"locks_at"`
+		LockedAt         time.Time           `json:"locked_at"`
+		EndedAt          time.Time           `json:"ended_at"`
+	}
+	StreamOnlineEventV1 struct {
+		ID string `json:"id"`
+		BroadcasterRef
+		Type      string    `json:"type"`
+		StartedAt time.Time `json:"started_at"`
+	}
+	StreamOfflineEventV1 struct {
+		BroadcasterRef
+	}
+	ChannelUpdateEventV2 struct {
+		BroadcasterRef
+		Title        string `json:"title"`
+		Language     string `json:"language"`
+		CategoryID   string `json:"category_id"`
+		CategoryName string `json:"category_name"`
+	}
+	ChannelBanEventV1 struct {
+		UserRef
+		ModeratorRef
+		Reason      string    `json:"reason"`
+		BannedAt    time.Time `json:"banned_at"`
+		EndsAt      time.Time `json:"ends_at"`
+		IsPermanent bool      `json:"is_permanent"`
+	}
+	ChannelUnbanEventV1 struct {
+		UserRef
+		ModeratorRef
+	}
+	ChannelModeratorAddEventV1    struct{ UserRef }
+	ChannelModeratorRemoveEventV1 struct{ UserRef }
+	ChannelVIPAddEventV1          struct{ UserRef }
+	ChannelVIPRemoveEventV1       struct{ UserRef }
+	ChatClearEventV1              struct{ BroadcasterRef }
+	ChatClearUserMessagesEventV1  struct {
+		TargetUserID    string `json:"target_user_id"`
+		TargetUserLogin string `json:"target_user_login"`
+		TargetUserName  string `json:"target_user_name"`
+	}
+	ChatMessageDeleteEventV1 struct {
+		TargetUserID    string `json:"target_user_id"`
+		TargetUserLogin string `json:"target_user_login"`
+		TargetUserName  string `json:"target_user_name"`
+		MessageID       string `json:"message_id"`
+	}
+	ChatNotificationEventV1 struct {
+		ChatterUserID      string `json:"chatter_user_id"`
+		ChatterUserLogin   string `json:"chatter_user_login"`
+		ChatterUserName    string `json:"chatter_user_name"`
+		ChatterIsAnonymous bool   `json:"chatter_is_anonymous"`
+		Color              string `json:"color"`
+		SystemMessage      string `json:"system_message"`
+		MessageID          string `json:"message_id"`
+		Message            struct {
+			Text      string            `json:"text"`
+			Fragments []MessageFragment `json:"fragments"`
+		} `json:"message"`
+		NoticeType string `json:"notice_type"`
+		Sub        *struct {
+			SubTier        string `json:"sub_tier"`
+			IsPrime        bool   `json:"is_prime"`
+			DurationMonths int    `json:"duration_months"`
+		} `json:"sub"`
+		Resub *struct {
+			CumulativeMonths  int    `json:"cumulative_months"`
+			DurationMonths    int    `json:"duration_months"`
+			StreakMonths      int    `json:"streak_months"`
+			SubTier           string `json:"sub_tier"`
+			IsPrime           bool   `json:"is_prime"`
+			IsGift            bool   `json:"is_gift"`
+			GifterIsAnonymous bool   `json:"gifter_is_anonymous"`
+			GifterUserID      string `json:"gifter_user_id"`
+			GifterUserLogin   string `json:"gifter_user_login"`
+			GifterUserName    string `json:"gifter_user_name"`
+		} `json:"resub"`
+		SubGift *struct {
+			DurationMonths     int    `json:"duration_months"`
+			CumulativeTotal    int    `json:"cumulative_total"`
+			RecipientUserID    string `json:"recipient_user_id"`
+			RecipientUserLogin string `json:"recipient_user_login"`
+			RecipientUserName  string `json:"recipient_user_name"`
+			SubTier            string `json:"sub_tier"`
+			CommunityGiftID    string `json:"community_gift_id"`
+		} `json:"sub_gift"`
+		CommunitySubGift *struct {
+			ID              string `json:"id"`
+			Total           int    `json:"total"`
+			SubTier         string `json:"sub_tier"`
+			CumulativeTotal int    `json:"cumulative_total"`
+		} `json:"community_sub_gift"`
+		GiftPaidUpgrade  *GifterNotice `json:"gift_paid_upgrade"`
+		PrimePaidUpgrade *struct {
+			SubTier string `json:"sub_tier"`
+		} `json:"prime_paid_upgrade"`
+		Raid *struct {
+			UserRef
+			ViewerCount     int    `json:"viewer_count"`
+			ProfileImageURL string `json:"profile_image_url"`
+		} `json:"raid"`
+		PayItForward *GifterNotice `json:"pay_it_forward"`
+		Announcement *struct {
+			Color string `json:"color"`
+		} `json:"announcement"`
+		BitsBadgeTier *struct {
+			Tier int `json:"tier"`
+		} `json:"bits_badge_tier"`
+		CharityDonation *struct {
+			CharityName string `json:"charity_name"`
+			Amount      Amount `json:"amount"`
+		} `json:"charity_donation"`
+	}
+	GifterNotice struct {
+		GifterIsAnonymous bool   `json:"gifter_is_anonymous"`
+		GifterUserID      string `json:"gifter_user_id"`
+		GifterUserLogin   string `json:"gifter_user_login"`
+		GifterUserName    string `json:"gifter_user_name"`
+	}
+)
+
+type eventKey struct {
+	eventType string
+	version   string
+}
+
+var eventDecoders = map[eventKey]func(json.RawMessage) (interface{}, error){
+	{helix.EventSubTypeChannelFollow, "2"}:                             decodeAs[ChannelFollowEventV2],
+	{helix.EventSubTypeChannelSubscription, "1"}:                       decodeAs[ChannelSubscribeEventV1],
+	{helix.EventSubTypeChannelSubscriptionMessage, "1"}:                decodeAs[ChannelSubscriptionMessageEventV1],
+	{helix.EventSubTypeChannelSubscriptionGift, "1"}:                   decodeAs[ChannelSubscriptionGiftEventV1],
+	{helix.EventSubTypeChannelCheer, "1"}:                              decodeAs[ChannelCheerEventV1],
+	{helix.EventSubTypeChannelRaid, "1"}:                               decodeAs[ChannelRaidEventV1],
+	{helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd, "1"}:    decodeAs[ChannelPointsRedemptionEventV1],
+	{helix.EventSubTypeChannelPointsCustomRewardRedemptionUpdate, "1"}: decodeAs[ChannelPointsRedemptionEventV1],
+	{helix.EventSubTypeChannelPointsCustomRewardAdd, "1"}:              decodeAs[ChannelPointsRewardEventV1],
+	{helix.EventSubTypeChannelPointsCustomRewardUpdate, "1"}:           decodeAs[ChannelPointsRewardEventV1],
+	{helix.EventSubTypeChannelPointsCustomRewardRemove, "1"}:           decodeAs[ChannelPointsRewardEventV1],
+	{EventSubTypeChannelPointsAutomaticRewardRedemptionAdd, "2"}:       decodeAs[ChannelPointsAutomaticRedemptionEventV2],
+	{helix.EventSubShoutoutCreate, "1"}:                                decodeAs[ShoutoutCreateEventV1],
+	{helix.EventSubShoutoutReceive, "1"}:                               decodeAs[ShoutoutReceiveEventV1],
+	{helix.EventSubTypeCharityDonation, "1"}:                           decodeAs[CharityDonationEventV1],
+	{helix.EventSubTypeCharityStart, "1"}:                              decodeAs[CharityCampaignEventV1],
+	{helix.EventSubTypeCharityProgress, "1"}:                           decodeAs[CharityCampaignEventV1],
+	{helix.EventSubTypeCharityStop, "1"}:                               decodeAs[CharityCampaignEventV1],
+	{helix.EventSubTypeChannelPollBegin, "1"}:                          decodeAs[ChannelPollEventV1],
+	{helix.EventSubTypeChannelPollProgress, "1"}:                       decodeAs[ChannelPollEventV1],
+	{helix.EventSubTypeChannelPollEnd, "1"}:                            decodeAs[ChannelPollEventV1],
+	{helix.EventSubTypeChannelPredictionBegin, "1"}:                    decodeAs[ChannelPredictionEventV1],
+	{helix.EventSubTypeChannelPredictionProgress, "1"}:                 decodeAs[ChannelPredictionEventV1],
+	{helix.EventSubTypeChannelPredictionLock, "1"}:                     decodeAs[ChannelPredictionEventV1],
+	{helix.EventSubTypeChannelPredictionEnd, "1"}:                      decodeAs[ChannelPredictionEventV1],
+	{helix.EventSubTypeStreamOnline, "1"}:                              decodeAs[StreamOnlineEventV1],
+	{helix.EventSubTypeStreamOffline, "1"}:                             decodeAs[StreamOfflineEventV1],
+	{helix.EventSubTypeChannelUpdate, "2"}:                             decodeAs[ChannelUpdateEventV2],
+	{helix.EventSubTypeChannelBan, "1"}:                                decodeAs[ChannelBanEventV1],
+	{helix.EventSubTypeChannelUnban, "1"}:                              decodeAs[ChannelUnbanEventV1],
+	{helix.EventSubTypeModeratorAdd, "1"}:                              decodeAs[ChannelModeratorAddEventV1],
+	{helix.EventSubTypeModeratorRemove, "1"}:                           decodeAs[ChannelModeratorRemoveEventV1],
+	{EventSubTypeChannelVIPAdd, "1"}:                                   decodeAs[ChannelVIPAddEventV1],
+	{EventSubTypeChannelVIPRemove, "1"}:                                decodeAs[ChannelVIPRemoveEventV1],
+	{helix.EventSubTypeChannelChatClear, "1"}:                          decodeAs[ChatClearEventV1],
+	{helix.EventSubTypeChannelChatClearUserMessages, "1"}:              decodeAs[ChatClearUserMessagesEventV1],
+	{helix.EventSubTypeChannelChatMessageDelete, "1"}:                  decodeAs[ChatMessageDeleteEventV1],
+	{helix.EventSubTypeChannelChatNotification, "1"}:                   decodeAs[ChatNotificationEventV1],
+}
+
+// DecodeEvent decodes the raw event of a notification into the struct registered for its subscription
+// type and version. Types without a struct return ErrUnknownEventType.
+func DecodeEvent(eventType string, version string, rawEvent json.RawMessage) (interface{}, error) {
+	decoder, ok := eventDecoders[eventKey{eventType, version}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s version %s", ErrUnknownEventType, eventType, version)
+	}
+
+	event, err := decoder(rawEvent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s version %s: %w", eventType, version, err)
+	}
+
+	return event, nil
+}
+
+func decodeAs[T any](rawEvent json.RawMessage) (interface{}, error) {
+	var event T
+	if err := json.Unmarshal(rawEvent, &event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// MessageFragmentsFromEmotes splits a message into text and emote fragments for events that only
+// carry emote positions. Emote positions are character indices into text, end is inclusive.
+func MessageFragmentsFromEmotes(text string, emotes []MessageEmote) []MessageFragment {
+	sorted := append([]MessageEmote(nil), emotes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Begin < sorted[j].Begin })
+
+	chars := []rune(text)
+	var fragments []MessageFragment
+	position := 0
+	for _, emote := range sorted {
+		if emote.Begin < position || emote.End < emote.Begin || emote.End >= len(chars) {
+			continue
+		}
+
+		if emote.Begin > position {
+			fragments = append(fragments, MessageFragment{Type: "text", Text: string(chars[position:emote.Begin])})
+		}
+		fragments = append(fragments, MessageFragment{
+			Type:  "emote",
+			Text:  string(chars[emote.Begin : emote.End+1]),
+			Emote: &FragmentEmote{ID: emote.ID},
+		})
+		position = emote.End + 1
+	}
+
+	if position < len(chars) {
+		fragments = append(fragments, MessageFragment{Type: "text", Text: string(chars[position:])})
+	}
+
+	return fragments
+}
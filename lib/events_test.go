@@ -0,0 +1,157 @@
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventType   string
+		version     string
+		rawEvent    string
+		wantUnknown bool
+		wantErr     bool
+	}{
+		{
+			name:      "known type",
+			eventType: helix.EventSubTypeChannelCheer,
+			version:   "1",
+			rawEvent:  `{"user_id":"1","bits":100}`,
+		},
+		{
+			name:        "unknown type",
+			eventType:   "channel.unknown",
+			version:     "1",
+			rawEvent:    `{}`,
+			wantUnknown: true,
+			wantErr:     true,
+		},
+		{
+			name:        "unknown version",
+			eventType:   helix.EventSubTypeChannelFollow,
+			version:     "1",
+			rawEvent:    `{}`,
+			wantUnknown: true,
+			wantErr:     true,
+		},
+		{
+			name:      "broken payload",
+			eventType: helix.EventSubTypeChannelCheer,
+			version:   "1",
+			rawEvent:  `{"bits":"many"}`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := DecodeEvent(tt.eventType, tt.version, json.RawMessage(tt.rawEvent))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeEvent returned error %v, want error %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrUnknownEventType) != tt.wantUnknown {
+				t.Errorf("DecodeEvent returned %v, want ErrUnknownEventType %v", err, tt.wantUnknown)
+			}
+			if err != nil && event != nil {
+				t.Errorf("DecodeEvent returned %v with error %v", event, err)
+			}
+		})
+	}
+}
+
+func TestDecodeEventType(t *testing.T) {
+	event, err := DecodeEvent(helix.EventSubTypeChannelCheer, "1", json.RawMessage(`{"user_login":"viewer","bits":100,"message":"Cheer100"}`))
+	if err != nil {
+		t.Fatalf("DecodeEvent returned %v", err)
+	}
+
+	cheer, ok := event.(ChannelCheerEventV1)
+	if !ok {
+		t.Fatalf("DecodeEvent returned %T, want ChannelCheerEventV1", event)
+	}
+	if cheer.UserLogin != "viewer" || cheer.Bits != 100 || cheer.Message != "Cheer100" {
+		t.Errorf("DecodeEvent returned %+v", cheer)
+	}
+}
+
+func TestMessageFragmentsFromEmotes(t *testing.T) {
+	text := func(text string) MessageFragment {
+		return MessageFragment{Type: "text", Text: text}
+	}
+	emote := func(text string, id string) MessageFragment {
+		return MessageFragment{Type: "emote", Text: text, Emote: &FragmentEmote{ID: id}}
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		emotes []MessageEmote
+		want   []MessageFragment
+	}{
+		{name: "empty message", text: ""},
+		{name: "no emotes", text: "hello", want: []MessageFragment{text("hello")}},
+		{
+			name:   "emote only",
+			text:   "Kappa",
+			emotes: []MessageEmote{{ID: "25", Begin: 0, End: 4}},
+			want:   []MessageFragment{emote("Kappa", "25")},
+		},
+		{
+			name:   "emote between text",
+			text:   "hi Kappa there",
+			emotes: []MessageEmote{{ID: "25", Begin: 3, End: 7}},
+			want:   []MessageFragment{text("hi "), emote("Kappa", "25"), text(" there")},
+		},
+		{
+			name:   "unsorted emotes",
+			text:   "Kappa PogChamp",
+			emotes: []MessageEmote{{ID: "88", Begin: 6, End: 13}, {ID: "25", Begin: 0, End: 4}},
+			want:   []MessageFragment{emote("Kappa", "25"), text(" "), emote("PogChamp", "88")},
+		},
+		{
+			name:   "indices count characters not bytes",
+			text:   "größer Kappa",
+			emotes: []MessageEmote{{ID: "25", Begin: 7, End: 11}},
+			want:   []MessageFragment{text("größer "), emote("Kappa", "25")},
+		},
+		{
+			name:   "emoji before emote",
+			text:   "🎉🎉 Kappa 🎉",
+			emotes: []MessageEmote{{ID: "25", Begin: 3, End: 7}},
+			want:   []MessageFragment{text("🎉🎉 "), emote("Kappa", "25"), text(" 🎉")},
+		},
+		{
+			name:   "end past the message",
+			text:   "hi Kappa",
+			emotes: []MessageEmote{{ID: "25", Begin: 3, End: 8}},
+			want:   []MessageFragment{text("hi Kappa")},
+		},
+		{
+			name:   "end before begin",
+			text:   "hi Kappa",
+			emotes: []MessageEmote{{ID: "25", Begin: 7, End: 3}},
+			want:   []MessageFragment{text("hi Kappa")},
+		},
+		{
+			name:   "overlapping emotes",
+			text:   "KappaKappa",
+			emotes: []MessageEmote{{ID: "25", Begin: 0, End: 6}, {ID: "26", Begin: 5, End: 9}},
+			want:   []MessageFragment{emote("KappaKa", "25"), text("ppa")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MessageFragmentsFromEmotes(tt.text, tt.emotes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MessageFragmentsFromEmotes(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package lib
+
+import "time"
+
+// GiftBombWindow is how long recipient subs are collected for a gift event. The recipient subs
+// may arrive before or after the gift event.
+const GiftBombWindow = 10 * time.Second
+
+// GiftBomb is a gift event with the recipients collected so far. Event is whatever the caller
+// wants back once the gift bomb completes.
+type GiftBomb[T any] struct {
+	CommunityGiftID string
+	GifterID        string
+	Gifter          string
+	Tier            int
+	Total           int
+	Recipients      []string
+	ReceivedAt      time.Time
+	Event           T
+
+	recipientIDs map[string]bool
+}
+
+type GiftedSub struct {
+	CommunityGiftID string
+	Tier            int
+	RecipientID     string
+	Recipient       string
+	ReceivedAt      time.Time
+}
+
+// communityGift is a community_sub_gift notice that arrived before its gift event.
+type communityGift struct {
+	id         string
+	gifterID   string
+	tier       int
+	total      int
+	receivedAt time.Time
+}
+
+// completedGiftBomb keeps the recipients of a completed gift bomb to drop late duplicate reports.
+type completedGiftBomb struct {
+	recipientIDs map[string]bool
+	completedAt  time.Time
+}
+
+// GiftBombs correlates gift events and recipient subs by the community gift id of the
+// channel.chat.notification community_sub_gift and sub_gift notices. Without those notices they
+// fall back to the tier and the order of arrival. The zero value is ready to use, it is not safe
+// for concurrent use.
+type GiftBombs[T any] struct {
+	pending        []*GiftBomb[T]
+	unclaimed      []GiftedSub
+	communityGifts []communityGift
+	completed      []completedGiftBomb
+}
+
+// Start tracks a gift event and claims recipient subs that arrived before it. It returns the gift
+// bombs that have all their recipients. gifterID and gifter are empty for anonymous gifts.
+func (g *GiftBombs[T]) Start(gifterID string, gifter string, tier int, total int, event T, now time.Time) []*GiftBomb[T] {
+	bomb := &GiftBomb[T]{
+		GifterID:     gifterID,
+		Gifter:       gifter,
+		Tier:         tier,
+		Total:        total,
+		ReceivedAt:   now,
+		Event:        event,
+		recipientIDs: make(map[string]bool),
+	}
+
+	for i, gift := range g.communityGifts {
+		if gift.matches(bomb.GifterID, bomb.Tier, bomb.Total) {
+			bomb.CommunityGiftID = gift.id
+			g.communityGifts = append(g.communityGifts[:i], g.communityGifts[i+1:]...)
+			break
+		}
+	}
+
+	g.pending = append(g.pending, bomb)
+	return g.claim(now)
+}
+
+// LinkCommunityGift sets the community gift id of the gift bomb started by the same gifter with
+// the same tier and total. If the gift event did not arrive yet the id is kept until it does.
+func (g *GiftBombs[T]) LinkCommunityGift(id string, gifterID string, tier int, total int, now time.Time) []*GiftBomb[T] {
+	gift := communityGift{id: id, gifterID: gifterID, tier: tier, total: total, receivedAt: now}
+
+	for _, bomb := range g.pending {
+		if bomb.CommunityGiftID == "" && gift.matches(bomb.GifterID, bomb.Tier, bomb.Total) {
+			bomb.CommunityGiftID = id
+			return g.claim(now)
+		}
+	}
+
+	g.communityGifts = append(g.communityGifts, gift)
+	return nil
+}
+
+// AddGiftedSub adds a recipient sub to its gift bomb, if there is none it waits for the gift
+// event. The same recipient may be reported by channel.subscribe and by a sub_gift notice, the
+// second report only adds the community gift id.
+func (g *GiftBombs[T]) AddGiftedSub(sub GiftedSub, now time.Time) []*GiftBomb[T] {
+	for _, bomb := range g.pending {
+		if bomb.recipientIDs[sub.RecipientID] {
+			return nil
+		}
+	}
+	for _, bomb := range g.completed {
+		if bomb.recipientIDs[sub.RecipientID] {
+			return nil
+		}
+	}
+
+	for i, unclaimed := range g.unclaimed {
+		if unclaimed.RecipientID != sub.RecipientID {
+			continue
+		}
+
+		if sub.CommunityGiftID == "" {
+			sub.CommunityGiftID = unclaimed.CommunityGiftID
+		}
+		sub.ReceivedAt = unclaimed.ReceivedAt
+		g.unclaimed = append(g.unclaimed[:i], g.unclaimed[i+1:]...)
+		break
+	}
+
+	g.unclaimed = append(g.unclaimed, sub)
+	return g.claim(now)
+}
+
+// Expire completes gift bombs whose recipients did not all arrive within GiftBombWindow with the
+// recipients known so far. It also returns the recipient subs and community gift ids that never
+// got a gift event.
+func (g *GiftBombs[T]) Expire(now time.Time) ([]*GiftBomb[T], []GiftedSub, []string) {
+	var completed []*GiftBomb[T]
+	var pending []*GiftBomb[T]
+	for _, bomb := range g.pending {
+		if now.Sub(bomb.ReceivedAt) < GiftBombWindow {
+			pending = append(pending, bomb)
+			continue
+		}
+
+		completed = append(completed, bomb)
+	}
+	g.pending = pending
+	g.remember(completed, now)
+
+	var expiredSubs []GiftedSub
+	var unclaimed []GiftedSub
+	for _, sub := range g.unclaimed {
+		if now.Sub(sub.ReceivedAt) < GiftBombWindow {
+			unclaimed = append(unclaimed, sub)
+			continue
+		}
+
+		expiredSubs = append(expiredSubs, sub)
+	}
+	g.unclaimed = unclaimed
+
+	var expiredGiftIDs []string
+	var communityGifts []communityGift
+	for _, gift := range g.communityGifts {
+		if now.Sub(gift.receivedAt) < GiftBombWindow {
+			communityGifts = append(communityGifts, gift)
+			continue
+		}
+
+		expiredGiftIDs = append(expiredGiftIDs, gift.id)
+	}
+	g.communityGifts = communityGifts
+
+	var recent []completedGiftBomb
+	for _, bomb := range g.completed {
+		if now.Sub(bomb.completedAt) < GiftBombWindow {
+			recent = append(recent, bomb)
+		}
+	}
+	g.completed = recent
+
+	return completed, expiredSubs, expiredGiftIDs
+}
+
+// claim moves unclaimed recipient subs to their gift bombs and returns the gift bombs that have
+// all their recipients.
+func (g *GiftBombs[T]) claim(now time.Time) []*GiftBomb[T] {
+	var unclaimed []GiftedSub
+	for _, sub := range g.unclaimed {
+		bomb := g.giftBombFor(sub)
+		if bomb == nil {
+			unclaimed = append(unclaimed, sub)
+			continue
+		}
+
+		bomb.recipientIDs[sub.RecipientID] = true
+		bomb.Recipients = append(bomb.Recipients, sub.Recipient)
+	}
+	g.unclaimed = unclaimed
+
+	var completed []*GiftBomb[T]
+	var pending []*GiftBomb[T]
+	for _, bomb := range g.pending {
+		if len(bomb.Recipients) < bomb.Total {
+			pending = append(pending, bomb)
+			continue
+		}
+
+		completed = append(completed, bomb)
+	}
+	g.pending = pending
+	g.remember(completed, now)
+
+	return completed
+}
+
+// giftBombFor returns the gift bomb with the community gift id of the sub. Subs without a
+// community gift id go to the oldest gift bomb of the same tier without a community gift id that
+// still has room.
+func (g *GiftBombs[T]) giftBombFor(sub GiftedSub) *GiftBomb[T] {
+	for _, bomb := range g.pending {
+		if len(bomb.Recipients) >= bomb.Total || bomb.CommunityGiftID != sub.CommunityGiftID {
+			continue
+		}
+		if sub.CommunityGiftID == "" && bomb.Tier != sub.Tier {
+			continue
+		}
+
+		return bomb
+	}
+
+	return nil
+}
+
+func (g *GiftBombs[T]) remember(completed []*GiftBomb[T], now time.Time) {
+	for _, bomb := range completed {
+		g.completed = append(g.completed, completedGiftBomb{recipientIDs: bomb.recipientIDs, completedAt: now})
+	}
+}
+
+func (gift communityGift) matches(gifterID string, tier int, total int) bool {
+	return gift.gifterID == gifterID && gift.tier == tier && gift.total == total
+}
@@ -0,0 +1,233 @@
+package lib
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type giftBombStep struct {
+	at time.Duration
+
+	// one of
+	start  *GiftBomb[string]
+	link   *communityGift
+	sub    *GiftedSub
+	expire bool
+}
+
+func startGift(gifterID string, tier int, total int) giftBombStep {
+	return giftBombStep{start: &GiftBomb[string]{GifterID: gifterID, Gifter: gifterID, Tier: tier, Total: total}}
+}
+
+func linkGift(id string, gifterID string, tier int, total int) giftBombStep {
+	return giftBombStep{link: &communityGift{id: id, gifterID: gifterID, tier: tier, total: total}}
+}
+
+func giftedSub(communityGiftID string, tier int, recipient string) giftBombStep {
+	return giftBombStep{sub: &GiftedSub{CommunityGiftID: communityGiftID, Tier: tier, RecipientID: recipient, Recipient: recipient}}
+}
+
+func expireAt(at time.Duration) giftBombStep {
+	return giftBombStep{at: at, expire: true}
+}
+
+func after(at time.Duration, step giftBombStep) giftBombStep {
+	step.at = at
+	return step
+}
+
+// completedAs describes a completed gift bomb as gifter:recipient,recipient
+func completedAs(bomb *GiftBomb[string]) string {
+	return fmt.Sprintf("%s:%s", bomb.Event, strings.Join(bomb.Recipients, ","))
+}
+
+func TestGiftBombs(t *testing.T) {
+	tests := []struct {
+		name          string
+		steps         []giftBombStep
+		wantCompleted []string
+		wantExpired   []string
+	}{
+		{
+			name: "recipients after the gift event",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 2),
+				giftedSub("", 1000, "r1"),
+				giftedSub("", 1000, "r2"),
+			},
+			wantCompleted: []string{"alice:r1,r2"},
+		},
+		{
+			name: "recipients before the gift event",
+			steps: []giftBombStep{
+				giftedSub("", 1000, "r1"),
+				giftedSub("", 1000, "r2"),
+				startGift("alice", 1000, 2),
+			},
+			wantCompleted: []string{"alice:r1,r2"},
+		},
+		{
+			name: "fallback by tier",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 1),
+				startGift("bob", 2000, 1),
+				giftedSub("", 2000, "r1"),
+				giftedSub("", 1000, "r2"),
+			},
+			wantCompleted: []string{"bob:r1", "alice:r2"},
+		},
+		{
+			name: "fallback by order of arrival",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 2),
+				startGift("bob", 1000, 1),
+				giftedSub("", 1000, "r1"),
+				giftedSub("", 1000, "r2"),
+				giftedSub("", 1000, "r3"),
+			},
+			wantCompleted: []string{"alice:r1,r2", "bob:r3"},
+		},
+		{
+			name: "community gift id",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 1),
+				startGift("bob", 1000, 1),
+				linkGift("gift-bob", "bob", 1000, 1),
+				giftedSub("gift-bob", 1000, "r1"),
+			},
+			wantCompleted: []string{"bob:r1"},
+		},
+		{
+			name: "community gift before the gift event",
+			steps: []giftBombStep{
+				linkGift("gift-bob", "bob", 1000, 1),
+				giftedSub("gift-bob", 1000, "r1"),
+				startGift("alice", 1000, 1),
+				startGift("bob", 1000, 1),
+			},
+			wantCompleted: []string{"bob:r1"},
+		},
+		{
+			name: "anonymous community gift",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 1),
+				startGift("", 1000, 1),
+				linkGift("gift-anonymous", "", 1000, 1),
+				giftedSub("gift-anonymous", 1000, "r1"),
+			},
+			wantCompleted: []string{":r1"},
+		},
+		{
+			name: "recipient reported twice",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 2),
+				giftedSub("", 1000, "r1"),
+				giftedSub("gift-alice", 1000, "r1"),
+				giftedSub("", 1000, "r2"),
+			},
+			wantCompleted: []string{"alice:r1,r2"},
+		},
+		{
+			name: "recipient reported twice before the gift event",
+			steps: []giftBombStep{
+				giftedSub("", 1000, "r1"),
+				giftedSub("gift-bob", 1000, "r1"),
+				startGift("alice", 1000, 1),
+				startGift("bob", 1000, 1),
+				linkGift("gift-bob", "bob", 1000, 1),
+			},
+			wantCompleted: []string{"bob:r1"},
+		},
+		{
+			name: "late duplicate of a completed gift bomb",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 1),
+				giftedSub("", 1000, "r1"),
+				startGift("bob", 1000, 1),
+				after(time.Second, giftedSub("gift-alice", 1000, "r1")),
+				after(2*time.Second, giftedSub("", 1000, "r1")),
+				after(3*time.Second, giftedSub("", 1000, "r2")),
+			},
+			wantCompleted: []string{"alice:r1", "bob:r2"},
+		},
+		{
+			name: "expires missing recipients",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 3),
+				giftedSub("", 1000, "r1"),
+				expireAt(GiftBombWindow - time.Millisecond),
+				expireAt(GiftBombWindow),
+			},
+			wantCompleted: []string{"alice:r1"},
+		},
+		{
+			name: "expires subs without a gift event",
+			steps: []giftBombStep{
+				giftedSub("", 1000, "r1"),
+				after(time.Second, linkGift("gift-bob", "bob", 1000, 1)),
+				expireAt(GiftBombWindow),
+				expireAt(GiftBombWindow + time.Second),
+				after(GiftBombWindow+time.Second, startGift("bob", 1000, 1)),
+			},
+			wantExpired: []string{"r1", "gift-bob"},
+		},
+		{
+			name: "recipients of completed gift bombs are forgotten after the window",
+			steps: []giftBombStep{
+				startGift("alice", 1000, 1),
+				giftedSub("", 1000, "r1"),
+				expireAt(GiftBombWindow),
+				after(GiftBombWindow, startGift("bob", 1000, 1)),
+				after(GiftBombWindow, giftedSub("", 1000, "r1")),
+			},
+			wantCompleted: []string{"alice:r1", "bob:r1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bombs GiftBombs[string]
+			startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			var completed []string
+			var expired []string
+			for _, step := range tt.steps {
+				now := startedAt.Add(step.at)
+
+				var done []*GiftBomb[string]
+				switch {
+				case step.start != nil:
+					done = bombs.Start(step.start.GifterID, step.start.Gifter, step.start.Tier, step.start.Total, step.start.Gifter, now)
+				case step.link != nil:
+					done = bombs.LinkCommunityGift(step.link.id, step.link.gifterID, step.link.tier, step.link.total, now)
+				case step.sub != nil:
+					sub := *step.sub
+					sub.ReceivedAt = now
+					done = bombs.AddGiftedSub(sub, now)
+				case step.expire:
+					var expiredSubs []GiftedSub
+					var expiredGiftIDs []string
+					done, expiredSubs, expiredGiftIDs = bombs.Expire(now)
+					for _, sub := range expiredSubs {
+						expired = append(expired, sub.RecipientID)
+					}
+					expired = append(expired, expiredGiftIDs...)
+				}
+
+				for _, bomb := range done {
+					completed = append(completed, completedAs(bomb))
+				}
+			}
+
+			if !reflect.DeepEqual(completed, tt.wantCompleted) {
+				t.Errorf("completed = %v, want %v", completed, tt.wantCompleted)
+			}
+			if !reflect.DeepEqual(expired, tt.wantExpired) {
+				t.Errorf("expired = %v, want %v", expired, tt.wantExpired)
+			}
+		})
+	}
+}
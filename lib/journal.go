@@ -0,0 +1,230 @@
+package lib
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// journalDedupeSize is how many message ids are remembered to drop redelivered notifications.
+const journalDedupeSize = 1000
+
+var ErrDuplicateEntry = errors.New("message id is already in the journal")
+
+type JournalEntry struct {
+	MessageID        string          `json:"message_id"`
+	MessageTimestamp string          `json:"message_timestamp"`
+	Type             string          `json:"type"`
+	Version          string          `json:"version"`
+	Event            json.RawMessage `json:"event"`
+}
+
+// Journal appends notifications to events.jsonl inside dir. Once the file grows over maxBytes it is
+// rotated to events.1.jsonl, older files move up by one and anything past maxFiles is deleted.
+// Entries with the message id of one of the last journaled entries are rejected.
+type Journal struct {
+	dir      string
+	maxBytes int64
+	maxFiles int
+
+	lock      sync.Mutex
+	file      *os.File
+	size      int64
+	seen      map[string]bool
+	seenOrder []string
+}
+
+func NewJournal(dir string, maxBytes int64, maxFiles int) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create journal dir: %w", err)
+	}
+
+	j := &Journal{dir: dir, maxBytes: maxBytes, maxFiles: maxFiles, seen: make(map[string]bool)}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+
+	// a reconnect right after a restart may redeliver the last notifications
+	entries, err := readJournalFile(j.path(0))
+	if err != nil {
+		j.Close()
+		return nil, fmt.Errorf("unable to read journal: %w", err)
+	}
+	for _, entry := range entries {
+		j.remember(entry.MessageID)
+	}
+
+	return j, nil
+}
+
+// Append returns ErrDuplicateEntry if an entry with the same message id was appended recently.
+func (j *Journal) Append(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to encode journal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.file == nil {
+		return errors.New("journal is closed")
+	}
+	if j.seen[entry.MessageID] {
+		return ErrDuplicateEntry
+	}
+
+	written, err := j.file.Write(line)
+	j.size += int64(written)
+	if err != nil {
+		return fmt.Errorf("unable to write journal entry: %w", err)
+	}
+	j.remember(entry.MessageID)
+
+	if j.size >= j.maxBytes {
+		return j.rotate()
+	}
+
+	return nil
+}
+
+// Recent returns up to limit entries of the given type, newest first. An empty type matches all
+// entries and a limit of zero or less returns everything. The files are read without holding the
+// lock, so Append does not wait for it. An entry that moves to the next file by a rotation while
+// reading is returned once.
+func (j *Journal) Recent(eventType string, limit int) ([]JournalEntry, error) {
+	j.lock.Lock()
+	paths := make([]string, j.maxFiles)
+	for i := range paths {
+		paths[i] = j.path(i)
+	}
+	j.lock.Unlock()
+
+	var result []JournalEntry
+	returned := make(map[string]bool)
+	for _, path := range paths {
+		entries, err := readJournalFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// a rotation may be moving the file right now
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+
+		for k := len(entries) - 1; k >= 0; k-- {
+			if eventType != "" && entries[k].Type != eventType {
+				continue
+			}
+			if entries[k].MessageID != "" && returned[entries[k].MessageID] {
+				continue
+			}
+			returned[entries[k].MessageID] = true
+
+			result = append(result, entries[k])
+			if limit > 0 && len(result) >= limit {
+				return result, nil
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (j *Journal) Close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.file.Close()
+	j.file = nil
+
+	return err
+}
+
+func (j *Journal) open() error {
+	file, err := os.OpenFile(j.path(0), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open journal: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("unable to open journal: %w", err)
+	}
+
+	j.file = file
+	j.size = info.Size()
+
+	return nil
+}
+
+func (j *Journal) rotate() error {
+	if err := j.file.Close(); err != nil {
+		return fmt.Errorf("unable to rotate journal: %w", err)
+	}
+	j.file = nil
+
+	if err := os.Remove(j.path(j.maxFiles - 1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to rotate journal: %w", err)
+	}
+	for i := j.maxFiles - 2; i >= 0; i-- {
+		if err := os.Rename(j.path(i), j.path(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unable to rotate journal: %w", err)
+		}
+	}
+
+	return j.open()
+}
+
+func (j *Journal) remember(messageID string) {
+	if messageID == "" || j.seen[messageID] {
+		return
+	}
+
+	j.seen[messageID] = true
+	j.seenOrder = append(j.seenOrder, messageID)
+	if len(j.seenOrder) > journalDedupeSize {
+		delete(j.seen, j.seenOrder[0])
+		j.seenOrder = j.seenOrder[1:]
+	}
+}
+
+func (j *Journal) path(index int) string {
+	if index == 0 {
+		return filepath.Join(j.dir, "events.jsonl")
+	}
+
+	return filepath.Join(j.dir, fmt.Sprintf("events.%d.jsonl", index))
+}
+
+// readJournalFile skips broken lines, e.g. one cut off by a crash while writing.
+func readJournalFile(path string) ([]JournalEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,247 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func journalEntry(id string, eventType string) JournalEntry {
+	return JournalEntry{
+		MessageID: id,
+		Type:      eventType,
+		Version:   "1",
+		Event:     []byte(`{}`),
+	}
+}
+
+func messageIDs(entries []JournalEntry) []string {
+	ids := []string{}
+	for _, entry := range entries {
+		ids = append(ids, entry.MessageID)
+	}
+	return ids
+}
+
+func TestJournalRotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxBytes  int64
+		maxFiles  int
+		appends   int
+		wantFiles []string
+		wantIDs   []string
+	}{
+		{
+			name:      "below max size",
+			maxBytes:  1 << 20,
+			maxFiles:  3,
+			appends:   3,
+			wantFiles: []string{"events.jsonl"},
+			wantIDs:   []string{"2", "1", "0"},
+		},
+		{
+			name:      "rotates every entry",
+			maxBytes:  1,
+			maxFiles:  3,
+			appends:   2,
+			wantFiles: []string{"events.1.jsonl", "events.2.jsonl", "events.jsonl"},
+			wantIDs:   []string{"1", "0"},
+		},
+		{
+			name:      "drops files past max files",
+			maxBytes:  1,
+			maxFiles:  3,
+			appends:   5,
+			wantFiles: []string{"events.1.jsonl", "events.2.jsonl", "events.jsonl"},
+			wantIDs:   []string{"4", "3"},
+		},
+		{
+			name:      "single file",
+			maxBytes:  1,
+			maxFiles:  1,
+			appends:   3,
+			wantFiles: []string{"events.jsonl"},
+			wantIDs:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			journal, err := NewJournal(dir, tt.maxBytes, tt.maxFiles)
+			if err != nil {
+				t.Fatalf("NewJournal returned %v", err)
+			}
+			defer journal.Close()
+
+			for i := 0; i < tt.appends; i++ {
+				if err := journal.Append(journalEntry(fmt.Sprint(i), "channel.follow")); err != nil {
+					t.Fatalf("Append returned %v", err)
+				}
+			}
+
+			files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range files {
+				files[i] = filepath.Base(files[i])
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("files = %v, want %v", files, tt.wantFiles)
+			}
+
+			entries, err := journal.Recent("", 0)
+			if err != nil {
+				t.Fatalf("Recent returned %v", err)
+			}
+			if ids := messageIDs(entries); !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Recent ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestJournalRecent(t *testing.T) {
+	journal, err := NewJournal(t.TempDir(), 1<<20, 3)
+	if err != nil {
+		t.Fatalf("NewJournal returned %v", err)
+	}
+	defer journal.Close()
+
+	for i, eventType := range []string{"channel.follow", "channel.cheer", "channel.follow", "channel.raid", "channel.follow"} {
+		if err := journal.Append(journalEntry(fmt.Sprint(i), eventType)); err != nil {
+			t.Fatalf("Append returned %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		eventType string
+		limit     int
+		wantIDs   []string
+	}{
+		{name: "all", wantIDs: []string{"4", "3", "2", "1", "0"}},
+		{name: "limit", limit: 2, wantIDs: []string{"4", "3"}},
+		{name: "by type", eventType: "channel.follow", wantIDs: []string{"4", "2", "0"}},
+		{name: "by type with limit", eventType: "channel.follow", limit: 2, wantIDs: []string{"4", "2"}},
+		{name: "negative limit", eventType: "channel.cheer", limit: -1, wantIDs: []string{"1"}},
+		{name: "unknown type", eventType: "channel.ban", wantIDs: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := journal.Recent(tt.eventType, tt.limit)
+			if err != nil {
+				t.Fatalf("Recent returned %v", err)
+			}
+			if ids := messageIDs(entries); !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Recent ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestJournalDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := NewJournal(dir, 1<<20, 3)
+	if err != nil {
+		t.Fatalf("NewJournal returned %v", err)
+	}
+
+	if err := journal.Append(journalEntry("a", "channel.follow")); err != nil {
+		t.Fatalf("Append returned %v", err)
+	}
+	if err := journal.Append(journalEntry("a", "channel.follow")); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("Append of a redelivered entry returned %v, want %v", err, ErrDuplicateEntry)
+	}
+	if err := journal.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+
+	if err := journal.Append(journalEntry("b", "channel.follow")); err == nil {
+		t.Error("Append to a closed journal succeeded")
+	}
+
+	// the dedupe set is seeded from the journal on disk
+	reopened, err := NewJournal(dir, 1<<20, 3)
+	if err != nil {
+		t.Fatalf("NewJournal returned %v", err)
+	}
+	defer reopened.Close()
+
+	if err := reopened.Append(journalEntry("a", "channel.follow")); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("Append after reopening returned %v, want %v", err, ErrDuplicateEntry)
+	}
+	if err := reopened.Append(journalEntry("b", "channel.follow")); err != nil {
+		t.Errorf("Append returned %v", err)
+	}
+}
+
+func TestJournalSkipsBrokenLines(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"message_id":"a","type":"channel.follow"}` + "\n" +
+		`{"message_id":"b","ty` + "\n" +
+		`{"message_id":"c","type":"channel.follow"}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "events.jsonl"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err := NewJournal(dir, 1<<20, 3)
+	if err != nil {
+		t.Fatalf("NewJournal returned %v", err)
+	}
+	defer journal.Close()
+
+	entries, err := journal.Recent("", 0)
+	if err != nil {
+		t.Fatalf("Recent returned %v", err)
+	}
+	if ids, want := messageIDs(entries), []string{"c", "a"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Recent ids = %v, want %v", ids, want)
+	}
+}
+
+func TestJournalRecentWhileAppending(t *testing.T) {
+	journal, err := NewJournal(t.TempDir(), 512, 3)
+	if err != nil {
+		t.Fatalf("NewJournal returned %v", err)
+	}
+	defer journal.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			if err := journal.Append(journalEntry(fmt.Sprint(i), "channel.follow")); err != nil {
+				t.Errorf("Append returned %v", err)
+				return
+			}
+		}
+	}()
+
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+
+		entries, err := journal.Recent("", 0)
+		if err != nil {
+			t.Fatalf("Recent returned %v", err)
+		}
+
+		returned := make(map[string]bool)
+		for _, entry := range entries {
+			if returned[entry.MessageID] {
+				t.Fatalf("Recent returned %s twice", entry.MessageID)
+			}
+			returned[entry.MessageID] = true
+		}
+	}
+}
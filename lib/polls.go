@@ -0,0 +1,39 @@
+package lib
+
+import "fmt"
+
+// limits of the create poll api
+const (
+	pollMinChoices     = 2
+	pollMaxChoices     = 5
+	pollMinDuration    = 15
+	pollMaxDuration    = 1800
+	pollMaxTitleLength = 60
+	pollMaxChoiceTitle = 25
+
+	pollMaxChannelPointsPerVote = 1000000
+)
+
+// ValidatePoll checks a poll against the limits of the create poll api before it is sent. Lengths
+// are counted in characters, a channelPointsPerVote of zero disables voting with channel points.
+func ValidatePoll(title string, choices []string, duration int, channelPointsPerVote int) error {
+	if title == "" || len([]rune(title)) > pollMaxTitleLength {
+		return fmt.Errorf("title has to be 1 to %d characters", pollMaxTitleLength)
+	}
+	if len(choices) < pollMinChoices || len(choices) > pollMaxChoices {
+		return fmt.Errorf("%d to %d choices are required", pollMinChoices, pollMaxChoices)
+	}
+	for _, choice := range choices {
+		if choice == "" || len([]rune(choice)) > pollMaxChoiceTitle {
+			return fmt.Errorf("choice %q has to be 1 to %d characters", choice, pollMaxChoiceTitle)
+		}
+	}
+	if duration < pollMinDuration || duration > pollMaxDuration {
+		return fmt.Errorf("duration has to be %d to %d seconds", pollMinDuration, pollMaxDuration)
+	}
+	if channelPointsPerVote < 0 || channelPointsPerVote > pollMaxChannelPointsPerVote {
+		return fmt.Errorf("channel points per vote have to be 1 to %d or 0 to disable them", pollMaxChannelPointsPerVote)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package lib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePoll(t *testing.T) {
+	choices := func(count int) []string {
+		result := make([]string, count)
+		for i := range result {
+			result[i] = string(rune('a' + i))
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		title    string
+		choices  []string
+		duration int
+		points   int
+		wantErr  string
+	}{
+		{name: "valid", title: "Next game?", choices: choices(2), duration: 60},
+		{name: "limits", title: strings.Repeat("t", 60), choices: []string{strings.Repeat("c", 25), "b", "c", "d", "e"}, duration: 1800, points: 1000000},
+		{name: "shortest duration", title: "t", choices: choices(2), duration: 15},
+		{name: "title counts characters", title: strings.Repeat("ü", 60), choices: choices(2), duration: 60},
+		{name: "choice counts characters", title: "t", choices: []string{strings.Repeat("🎉", 25), "b"}, duration: 60},
+		{name: "empty title", title: "", choices: choices(2), duration: 60, wantErr: "title"},
+		{name: "long title", title: strings.Repeat("t", 61), choices: choices(2), duration: 60, wantErr: "title"},
+		{name: "one choice", title: "t", choices: choices(1), duration: 60, wantErr: "choices"},
+		{name: "too many choices", title: "t", choices: choices(6), duration: 60, wantErr: "choices"},
+		{name: "empty choice", title: "t", choices: []string{"a", ""}, duration: 60, wantErr: "choice \""},
+		{name: "long choice", title: "t", choices: []string{"a", strings.Repeat("c", 26)}, duration: 60, wantErr: "choice \""},
+		{name: "short duration", title: "t", choices: choices(2), duration: 14, wantErr: "duration"},
+		{name: "long duration", title: "t", choices: choices(2), duration: 1801, wantErr: "duration"},
+		{name: "channel points per vote", title: "t", choices: choices(2), duration: 60, points: 1},
+		{name: "most channel points per vote", title: "t", choices: choices(2), duration: 60, points: 1000000},
+		{name: "too many channel points per vote", title: "t", choices: choices(2), duration: 60, points: 1000001, wantErr: "channel points"},
+		{name: "negative channel points per vote", title: "t", choices: choices(2), duration: 60, points: -1, wantErr: "channel points"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePoll(tt.title, tt.choices, tt.duration, tt.points)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidatePoll returned %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidatePoll returned %v, want an error about the %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package lib
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RecordedFrame is one line of a recording, Frame is the websocket message exactly as received.
+type RecordedFrame struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Frame      json.RawMessage `json:"frame"`
+}
+
+// Recorder appends every websocket frame to a jsonl file.
+type Recorder struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create recording dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open recording: %w", err)
+	}
+
+	return &Recorder{file: file}, nil
+}
+
+func (r *Recorder) Record(frame []byte) error {
+	line, err := json.Marshal(RecordedFrame{ReceivedAt: time.Now().UTC(), Frame: frame})
+	if err != nil {
+		return fmt.Errorf("unable to encode recorded frame: %w", err)
+	}
+	line = append(line, '\n')
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.file == nil {
+		return errors.New("recording is closed")
+	}
+
+	if _, err := r.file.Write(line); err != nil {
+		return fmt.Errorf("unable to write recorded frame: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
+
+func ReadRecording(path string) ([]RecordedFrame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open recording: %w", err)
+	}
+	defer file.Close()
+
+	var frames []RecordedFrame
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var frame RecordedFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			continue
+		}
+		frames = append(frames, frame)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return frames, fmt.Errorf("unable to read recording: %w", err)
+	}
+
+	return frames, nil
+}
+
+// Player sends recorded frames with the time between them divided by speed. A speed of zero or
+// less plays in steps, every call to Step sends the next frame.
+type Player struct {
+	frames []RecordedFrame
+	speed  float64
+
+	step chan struct{}
+	stop chan struct{}
+	once sync.Once
+}
+
+func NewPlayer(frames []RecordedFrame, speed float64) *Player {
+	return &Player{
+		frames: frames,
+		speed:  speed,
+		step:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Play blocks until every frame was sent, Stop was called or send returned an error.
+func (p *Player) Play(send func(frame RecordedFrame) error) error {
+	for i, frame := range p.frames {
+		var wait <-chan time.Time
+		switch {
+		case p.speed <= 0:
+			// stepped, wait for Step
+		case i == 0:
+			wait = time.After(0)
+		default:
+			gap := frame.ReceivedAt.Sub(p.frames[i-1].ReceivedAt)
+			wait = time.After(time.Duration(float64(gap) / p.speed))
+		}
+
+		select {
+		case <-p.stop:
+			return nil
+		case <-p.step:
+		case <-wait:
+		}
+
+		if err := send(frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Step sends the next frame right away, also while playing with a speed.
+func (p *Player) Step() {
+	select {
+	case p.step <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Player) Stop() {
+	p.once.Do(func() { close(p.stop) })
+}
+
+// DecodeFrame parses a recorded frame the same way frames of a live connection are parsed.
+func DecodeFrame(frame RecordedFrame) (TwitchMessage, error) {
+	var msg TwitchMessage
+	if err := json.Unmarshal(frame.Frame, &msg); err != nil {
+		return msg, fmt.Errorf("unable to parse recorded frame: %w", err)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,195 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordingRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recordings", "session.jsonl")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("NewRecorder returned %v", err)
+	}
+
+	frames := []string{
+		`{"metadata":{"message_id":"1","message_type":"session_welcome"},"payload":{}}`,
+		`{"metadata":{"message_id":"2","message_type":"session_keepalive"},"payload":{}}`,
+	}
+	for _, frame := range frames {
+		if err := recorder.Record([]byte(frame)); err != nil {
+			t.Fatalf("Record returned %v", err)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+	if err := recorder.Record([]byte(frames[0])); err == nil {
+		t.Error("Record on a closed recorder succeeded")
+	}
+
+	// a frame cut off by a crash is skipped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"received_at":"2024-`)
+	file.Close()
+
+	recorded, err := ReadRecording(path)
+	if err != nil {
+		t.Fatalf("ReadRecording returned %v", err)
+	}
+	if len(recorded) != len(frames) {
+		t.Fatalf("ReadRecording returned %d frames, want %d", len(recorded), len(frames))
+	}
+	for i, frame := range recorded {
+		if string(frame.Frame) != frames[i] {
+			t.Errorf("frame %d = %s, want %s", i, frame.Frame, frames[i])
+		}
+
+		msg, err := DecodeFrame(frame)
+		if err != nil {
+			t.Fatalf("DecodeFrame returned %v", err)
+		}
+		if want := fmt.Sprint(i + 1); msg.Metadata.ID != want {
+			t.Errorf("frame %d decoded with message id %q, want %q", i, msg.Metadata.ID, want)
+		}
+	}
+
+	if _, err := ReadRecording(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("ReadRecording of a missing file succeeded")
+	}
+}
+
+func recordedFrames(gaps ...time.Duration) []RecordedFrame {
+	receivedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	frames := []RecordedFrame{{ReceivedAt: receivedAt, Frame: []byte(`"0"`)}}
+	for i, gap := range gaps {
+		receivedAt = receivedAt.Add(gap)
+		frames = append(frames, RecordedFrame{ReceivedAt: receivedAt, Frame: []byte{'"', byte('1' + i), '"'}})
+	}
+	return frames
+}
+
+func TestPlayerSpeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		frames      []RecordedFrame
+		speed       float64
+		wantMinimum time.Duration
+	}{
+		{name: "no frames", speed: 1},
+		{name: "single frame", frames: recordedFrames(), speed: 1},
+		{name: "real time", frames: recordedFrames(20*time.Millisecond, 20*time.Millisecond), speed: 1, wantMinimum: 40 * time.Millisecond},
+		{name: "fast forward", frames: recordedFrames(time.Second, time.Second), speed: 50, wantMinimum: 40 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []RecordedFrame
+			startedAt := time.Now()
+			err := NewPlayer(tt.frames, tt.speed).Play(func(frame RecordedFrame) error {
+				sent = append(sent, frame)
+				return nil
+			})
+			elapsed := time.Since(startedAt)
+
+			if err != nil {
+				t.Fatalf("Play returned %v", err)
+			}
+			if !reflect.DeepEqual(sent, tt.frames) {
+				t.Errorf("sent %v, want %v", sent, tt.frames)
+			}
+			if elapsed < tt.wantMinimum {
+				t.Errorf("played in %s, want at least %s", elapsed, tt.wantMinimum)
+			}
+		})
+	}
+}
+
+func TestPlayerStep(t *testing.T) {
+	frames := recordedFrames(time.Hour, time.Hour)
+	player := NewPlayer(frames, 0)
+
+	sent := make(chan RecordedFrame)
+	done := make(chan error)
+	go func() {
+		done <- player.Play(func(frame RecordedFrame) error {
+			sent <- frame
+			return nil
+		})
+	}()
+
+	for i := range frames {
+		select {
+		case <-sent:
+			t.Fatalf("frame %d was sent before Step", i)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		player.Step()
+		select {
+		case frame := <-sent:
+			if !reflect.DeepEqual(frame, frames[i]) {
+				t.Errorf("step %d sent %v, want %v", i, frame, frames[i])
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("step %d sent no frame", i)
+		}
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Play returned %v", err)
+	}
+}
+
+func TestPlayerStop(t *testing.T) {
+	player := NewPlayer(recordedFrames(time.Hour), 1)
+
+	done := make(chan error)
+	sent := 0
+	go func() {
+		done <- player.Play(func(frame RecordedFrame) error {
+			sent++
+			return nil
+		})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	player.Stop()
+	player.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Play returned %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Play did not return after Stop")
+	}
+	if sent != 1 {
+		t.Errorf("sent %d frames, want 1", sent)
+	}
+}
+
+func TestPlayerSendError(t *testing.T) {
+	sendErr := errors.New("connection closed")
+	sent := 0
+	err := NewPlayer(recordedFrames(0, 0), 1).Play(func(frame RecordedFrame) error {
+		sent++
+		return sendErr
+	})
+
+	if !errors.Is(err, sendErr) {
+		t.Errorf("Play returned %v, want %v", err, sendErr)
+	}
+	if sent != 1 {
+		t.Errorf("sent %d frames, want 1", sent)
+	}
+}
@@ -0,0 +1,98 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	RuleOperatorEquals    = "equals"
+	RuleOperatorNotEquals = "not_equals"
+	RuleOperatorContains  = "contains"
+	RuleOperatorRegex     = "regex"
+	RuleOperatorRange     = "range"
+	RuleOperatorAtLeast   = "at_least"
+	RuleOperatorAtMost    = "at_most"
+)
+
+type RuleCondition struct {
+	Field    string
+	Operator string
+	Value    string
+	Min      float64
+	Max      float64
+}
+
+// MatchRuleCondition compares value with the condition. Compiled regex patterns are kept in
+// patterns keyed by the pattern.
+func MatchRuleCondition(condition RuleCondition, value interface{}, patterns map[string]*regexp.Regexp) (bool, error) {
+	text := fmt.Sprint(value)
+
+	switch condition.Operator {
+	case RuleOperatorEquals:
+		return strings.EqualFold(text, condition.Value), nil
+	case RuleOperatorNotEquals:
+		return !strings.EqualFold(text, condition.Value), nil
+	case RuleOperatorContains:
+		return strings.Contains(strings.ToLower(text), strings.ToLower(condition.Value)), nil
+	case RuleOperatorRegex:
+		pattern, ok := patterns[condition.Value]
+		if !ok {
+			var err error
+			pattern, err = regexp.Compile(condition.Value)
+			if err != nil {
+				return false, fmt.Errorf("invalid regex %s: %w", condition.Value, err)
+			}
+			patterns[condition.Value] = pattern
+		}
+		return pattern.MatchString(text), nil
+	case RuleOperatorRange, RuleOperatorAtLeast, RuleOperatorAtMost:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return false, nil
+		}
+
+		aboveMin := number >= condition.Min
+		belowMax := number <= condition.Max
+		switch condition.Operator {
+		case RuleOperatorAtLeast:
+			return aboveMin, nil
+		case RuleOperatorAtMost:
+			return belowMax, nil
+		}
+		return aboveMin && belowMax, nil
+	default:
+		return false, fmt.Errorf("unknown operator %s", condition.Operator)
+	}
+}
+
+// LookupField follows a dot separated path through nested dictionaries and arrays.
+func LookupField(event map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = event
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	if current == nil {
+		return nil, false
+	}
+
+	return current, true
+}
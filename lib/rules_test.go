@@ -0,0 +1,124 @@
+package lib
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestLookupField(t *testing.T) {
+	event := map[string]interface{}{
+		"user_login": "viewer",
+		"bits":       100,
+		"reward": map[string]interface{}{
+			"title": "Hydrate",
+			"cost":  float64(500),
+		},
+		"message": map[string]interface{}{
+			"fragments": []interface{}{
+				map[string]interface{}{"type": "text", "text": "hello"},
+				map[string]interface{}{"type": "emote", "text": "Kappa"},
+			},
+		},
+		"empty": nil,
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		want      interface{}
+		wantFound bool
+	}{
+		{name: "top level", path: "user_login", want: "viewer", wantFound: true},
+		{name: "nested", path: "reward.title", want: "Hydrate", wantFound: true},
+		{name: "number", path: "reward.cost", want: float64(500), wantFound: true},
+		{name: "int", path: "bits", want: 100, wantFound: true},
+		{name: "array index", path: "message.fragments.1.text", want: "Kappa", wantFound: true},
+		{name: "missing key", path: "reward.prompt"},
+		{name: "missing parent", path: "poll.title"},
+		{name: "index out of range", path: "message.fragments.2.text"},
+		{name: "negative index", path: "message.fragments.-1.text"},
+		{name: "non numeric index", path: "message.fragments.first"},
+		{name: "path through a value", path: "user_login.length"},
+		{name: "nil value", path: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := LookupField(event, tt.path)
+			if found != tt.wantFound || got != tt.want {
+				t.Errorf("LookupField(%q) = %v, %v, want %v, %v", tt.path, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestMatchRuleCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition RuleCondition
+		value     interface{}
+		want      bool
+		wantErr   bool
+	}{
+		{name: "equals ignores case", condition: RuleCondition{Operator: RuleOperatorEquals, Value: "hydrate"}, value: "Hydrate", want: true},
+		{name: "equals mismatch", condition: RuleCondition{Operator: RuleOperatorEquals, Value: "hydrate"}, value: "Stretch"},
+		{name: "equals number", condition: RuleCondition{Operator: RuleOperatorEquals, Value: "500"}, value: float64(500), want: true},
+		{name: "not equals", condition: RuleCondition{Operator: RuleOperatorNotEquals, Value: "hydrate"}, value: "Stretch", want: true},
+		{name: "not equals ignores case", condition: RuleCondition{Operator: RuleOperatorNotEquals, Value: "hydrate"}, value: "HYDRATE"},
+		{name: "contains ignores case", condition: RuleCondition{Operator: RuleOperatorContains, Value: "DRAT"}, value: "Hydrate", want: true},
+		{name: "contains mismatch", condition: RuleCondition{Operator: RuleOperatorContains, Value: "water"}, value: "Hydrate"},
+		{name: "regex", condition: RuleCondition{Operator: RuleOperatorRegex, Value: `^!so @\w+$`}, value: "!so @viewer", want: true},
+		{name: "regex mismatch", condition: RuleCondition{Operator: RuleOperatorRegex, Value: `^!so @\w+$`}, value: "so @viewer"},
+		{name: "invalid regex", condition: RuleCondition{Operator: RuleOperatorRegex, Value: `(`}, value: "(", wantErr: true},
+		{name: "range inside", condition: RuleCondition{Operator: RuleOperatorRange, Min: 100, Max: 500}, value: float64(250), want: true},
+		{name: "range bounds are inclusive", condition: RuleCondition{Operator: RuleOperatorRange, Min: 100, Max: 500}, value: float64(500), want: true},
+		{name: "range below", condition: RuleCondition{Operator: RuleOperatorRange, Min: 100, Max: 500}, value: float64(99)},
+		{name: "range above", condition: RuleCondition{Operator: RuleOperatorRange, Min: 100, Max: 500}, value: float64(501)},
+		{name: "range of a numeric string", condition: RuleCondition{Operator: RuleOperatorRange, Min: 1, Max: 3}, value: "2", want: true},
+		{name: "range of text", condition: RuleCondition{Operator: RuleOperatorRange, Min: 1, Max: 3}, value: "two"},
+		{name: "at least", condition: RuleCondition{Operator: RuleOperatorAtLeast, Min: 100}, value: float64(100), want: true},
+		{name: "at least ignores max", condition: RuleCondition{Operator: RuleOperatorAtLeast, Min: 100}, value: float64(1000), want: true},
+		{name: "at least below", condition: RuleCondition{Operator: RuleOperatorAtLeast, Min: 100}, value: float64(10)},
+		{name: "at most", condition: RuleCondition{Operator: RuleOperatorAtMost, Max: 10}, value: float64(-5), want: true},
+		{name: "at most above", condition: RuleCondition{Operator: RuleOperatorAtMost, Max: 10}, value: float64(11)},
+		{name: "equals int", condition: RuleCondition{Operator: RuleOperatorEquals, Value: "500"}, value: 500, want: true},
+		{name: "contains int", condition: RuleCondition{Operator: RuleOperatorContains, Value: "50"}, value: 1500, want: true},
+		{name: "at least int", condition: RuleCondition{Operator: RuleOperatorAtLeast, Min: 100}, value: 100, want: true},
+		{name: "at least int below", condition: RuleCondition{Operator: RuleOperatorAtLeast, Min: 100}, value: 99},
+		{name: "at most int", condition: RuleCondition{Operator: RuleOperatorAtMost, Max: 10}, value: -5, want: true},
+		{name: "range int", condition: RuleCondition{Operator: RuleOperatorRange, Min: 100, Max: 500}, value: 500, want: true},
+		{name: "range int above", condition: RuleCondition{Operator: RuleOperatorRange, Min: 100, Max: 500}, value: 501},
+		{name: "at least int64", condition: RuleCondition{Operator: RuleOperatorAtLeast, Min: 100}, value: int64(100), want: true},
+		{name: "at most int64", condition: RuleCondition{Operator: RuleOperatorAtMost, Max: 1e12}, value: int64(1e12 + 1)},
+		{name: "range int64", condition: RuleCondition{Operator: RuleOperatorRange, Min: 1, Max: 3}, value: int64(2), want: true},
+		{name: "fraction against whole bounds", condition: RuleCondition{Operator: RuleOperatorRange, Min: 1, Max: 2}, value: 2.5},
+		{name: "unknown operator", condition: RuleCondition{Operator: "starts_with", Value: "a"}, value: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchRuleCondition(tt.condition, tt.value, make(map[string]*regexp.Regexp))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MatchRuleCondition returned error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MatchRuleCondition = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchRuleConditionCachesPatterns(t *testing.T) {
+	patterns := make(map[string]*regexp.Regexp)
+	condition := RuleCondition{Operator: RuleOperatorRegex, Value: "^a+$"}
+
+	for _, value := range []string{"aaa", "b"} {
+		if _, err := MatchRuleCondition(condition, value, patterns); err != nil {
+			t.Fatalf("MatchRuleCondition returned %v", err)
+		}
+	}
+
+	if len(patterns) != 1 || patterns["^a+$"] == nil {
+		t.Errorf("patterns = %v, want the compiled ^a+$", patterns)
+	}
+}
@@ -0,0 +1,156 @@
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+type RaidStat struct {
+	FromUserName string `json:"from_user_name"`
+	Viewers      int    `json:"viewers"`
+	ReceivedAt   int64  `json:"received_at"`
+}
+
+// DonationTotal is the sum of donations in one currency in minor units.
+type DonationTotal struct {
+	Value         int `json:"value"`
+	DecimalPlaces int `json:"decimal_places"`
+}
+
+// SessionStats sums up the events of one stream. Anonymous gifters and cheerers are counted under
+// an empty name. StreamStartedAt is zero if the stats were started while offline.
+type SessionStats struct {
+	StartedAt           int64                    `json:"started_at"`
+	StreamStartedAt     int64                    `json:"stream_started_at"`
+	Followers           []string                 `json:"followers"`
+	SubsByTier          map[string]int           `json:"subs_by_tier"`
+	GiftedSubsByGifter  map[string]int           `json:"gifted_subs_by_gifter"`
+	BitsByUser          map[string]int           `json:"bits_by_user"`
+	Raids               []RaidStat               `json:"raids"`
+	RedemptionsByReward map[string]int           `json:"redemptions_by_reward"`
+	DonationsByCurrency map[string]DonationTotal `json:"donations_by_currency"`
+}
+
+func NewSessionStats(startedAt time.Time) *SessionStats {
+	stats := &SessionStats{StartedAt: startedAt.Unix()}
+	stats.init()
+
+	return stats
+}
+
+// LoadSessionStats reads stats saved with Save. A missing file results in empty stats.
+func LoadSessionStats(path string) (*SessionStats, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewSessionStats(time.Now()), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read session stats: %w", err)
+	}
+
+	stats := new(SessionStats)
+	if err := json.Unmarshal(content, stats); err != nil {
+		return nil, fmt.Errorf("unable to decode session stats: %w", err)
+	}
+	stats.init()
+
+	return stats, nil
+}
+
+// Save writes the stats to a temporary file first, so a crash does not leave a broken file behind.
+func (s *SessionStats) Save(path string) error {
+	content, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("unable to encode session stats: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create session stats dir: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return fmt.Errorf("unable to write session stats: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("unable to write session stats: %w", err)
+	}
+
+	return nil
+}
+
+// Record adds a decoded event to the stats and reports whether it changed them. Raids started by
+// broadcasterUserID are not counted.
+func (s *SessionStats) Record(eventType string, event interface{}, broadcasterUserID string) bool {
+	switch event := event.(type) {
+	case ChannelFollowEventV2:
+		s.Followers = append(s.Followers, event.UserName)
+	case ChannelSubscribeEventV1:
+		// gifted subs are counted for the gifter
+		if event.IsGift {
+			return false
+		}
+		s.SubsByTier[event.Tier]++
+	case ChannelSubscriptionMessageEventV1:
+		s.SubsByTier[event.Tier]++
+	case ChannelSubscriptionGiftEventV1:
+		var gifter string
+		if !event.IsAnonymous {
+			gifter = event.UserName
+		}
+		s.GiftedSubsByGifter[gifter] += event.Total
+	case ChannelCheerEventV1:
+		var cheerer string
+		if !event.IsAnonymous {
+			cheerer = event.UserName
+		}
+		s.BitsByUser[cheerer] += event.Bits
+	case ChannelRaidEventV1:
+		if event.FromBroadcasterUserID == broadcasterUserID {
+			return false
+		}
+		s.Raids = append(s.Raids, RaidStat{
+			FromUserName: event.FromBroadcasterUserName,
+			Viewers:      event.Viewers,
+			ReceivedAt:   time.Now().Unix(),
+		})
+	case ChannelPointsRedemptionEventV1:
+		if eventType != helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd {
+			return false
+		}
+		s.RedemptionsByReward[event.Reward.Title]++
+	case CharityDonationEventV1:
+		total := s.DonationsByCurrency[event.Amount.Currency]
+		total.Value += event.Amount.Value
+		total.DecimalPlaces = event.Amount.DecimalPlaces
+		s.DonationsByCurrency[event.Amount.Currency] = total
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (s *SessionStats) init() {
+	if s.SubsByTier == nil {
+		s.SubsByTier = make(map[string]int)
+	}
+	if s.GiftedSubsByGifter == nil {
+		s.GiftedSubsByGifter = make(map[string]int)
+	}
+	if s.BitsByUser == nil {
+		s.BitsByUser = make(map[string]int)
+	}
+	if s.RedemptionsByReward == nil {
+		s.RedemptionsByReward = make(map[string]int)
+	}
+	if s.DonationsByCurrency == nil {
+		s.DonationsByCurrency = make(map[string]DonationTotal)
+	}
+}
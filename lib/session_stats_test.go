@@ -0,0 +1,269 @@
+package lib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+var sessionStartedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func statsWith(change func(stats *SessionStats)) *SessionStats {
+	stats := NewSessionStats(sessionStartedAt)
+	change(stats)
+	return stats
+}
+
+func TestSessionStatsRecord(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventType   string
+		version     string
+		rawEvent    string
+		wantChanged bool
+		want        *SessionStats
+	}{
+		{
+			name:        "follow",
+			eventType:   helix.EventSubTypeChannelFollow,
+			version:     "2",
+			rawEvent:    `{"user_name":"Viewer"}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.Followers = []string{"Viewer"} }),
+		},
+		{
+			name:        "sub",
+			eventType:   helix.EventSubTypeChannelSubscription,
+			version:     "1",
+			rawEvent:    `{"user_name":"Viewer","tier":"2000"}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.SubsByTier["2000"] = 1 }),
+		},
+		{
+			name:      "gifted sub is counted for the gifter",
+			eventType: helix.EventSubTypeChannelSubscription,
+			version:   "1",
+			rawEvent:  `{"user_name":"Viewer","tier":"1000","is_gift":true}`,
+			want:      statsWith(func(s *SessionStats) {}),
+		},
+		{
+			name:        "resub",
+			eventType:   helix.EventSubTypeChannelSubscriptionMessage,
+			version:     "1",
+			rawEvent:    `{"user_name":"Viewer","tier":"1000","cumulative_months":5}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.SubsByTier["1000"] = 1 }),
+		},
+		{
+			name:        "gift",
+			eventType:   helix.EventSubTypeChannelSubscriptionGift,
+			version:     "1",
+			rawEvent:    `{"user_name":"Gifter","tier":"1000","total":5}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.GiftedSubsByGifter["Gifter"] = 5 }),
+		},
+		{
+			name:        "anonymous gift",
+			eventType:   helix.EventSubTypeChannelSubscriptionGift,
+			version:     "1",
+			rawEvent:    `{"tier":"1000","total":3,"is_anonymous":true}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.GiftedSubsByGifter[""] = 3 }),
+		},
+		{
+			name:        "cheer",
+			eventType:   helix.EventSubTypeChannelCheer,
+			version:     "1",
+			rawEvent:    `{"user_name":"Viewer","bits":100}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.BitsByUser["Viewer"] = 100 }),
+		},
+		{
+			name:        "anonymous cheer",
+			eventType:   helix.EventSubTypeChannelCheer,
+			version:     "1",
+			rawEvent:    `{"user_name":"AnonymousCheerer","bits":100,"is_anonymous":true}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.BitsByUser[""] = 100 }),
+		},
+		{
+			name:        "incoming raid",
+			eventType:   helix.EventSubTypeChannelRaid,
+			version:     "1",
+			rawEvent:    `{"from_broadcaster_user_id":"2","from_broadcaster_user_name":"Raider","to_broadcaster_user_id":"1","viewers":42}`,
+			wantChanged: true,
+			want: statsWith(func(s *SessionStats) {
+				s.Raids = []RaidStat{{FromUserName: "Raider", Viewers: 42}}
+			}),
+		},
+		{
+			name:      "outgoing raid",
+			eventType: helix.EventSubTypeChannelRaid,
+			version:   "1",
+			rawEvent:  `{"from_broadcaster_user_id":"1","to_broadcaster_user_id":"2","viewers":42}`,
+			want:      statsWith(func(s *SessionStats) {}),
+		},
+		{
+			name:        "redemption",
+			eventType:   helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd,
+			version:     "1",
+			rawEvent:    `{"user_name":"Viewer","reward":{"title":"Hydrate"}}`,
+			wantChanged: true,
+			want:        statsWith(func(s *SessionStats) { s.RedemptionsByReward["Hydrate"] = 1 }),
+		},
+		{
+			name:      "redemption update",
+			eventType: helix.EventSubTypeChannelPointsCustomRewardRedemptionUpdate,
+			version:   "1",
+			rawEvent:  `{"user_name":"Viewer","status":"fulfilled","reward":{"title":"Hydrate"}}`,
+			want:      statsWith(func(s *SessionStats) {}),
+		},
+		{
+			name:        "donation",
+			eventType:   helix.EventSubTypeCharityDonation,
+			version:     "1",
+			rawEvent:    `{"user_name":"Viewer","amount":{"value":500,"decimal_places":2,"currency":"USD"}}`,
+			wantChanged: true,
+			want: statsWith(func(s *SessionStats) {
+				s.DonationsByCurrency["USD"] = DonationTotal{Value: 500, DecimalPlaces: 2}
+			}),
+		},
+		{
+			name:      "untracked event",
+			eventType: helix.EventSubTypeStreamOnline,
+			version:   "1",
+			rawEvent:  `{"type":"live"}`,
+			want:      statsWith(func(s *SessionStats) {}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := DecodeEvent(tt.eventType, tt.version, json.RawMessage(tt.rawEvent))
+			if err != nil {
+				t.Fatalf("DecodeEvent returned %v", err)
+			}
+
+			stats := NewSessionStats(sessionStartedAt)
+			if changed := stats.Record(tt.eventType, event, "1"); changed != tt.wantChanged {
+				t.Errorf("Record returned %v, want %v", changed, tt.wantChanged)
+			}
+
+			for i := range stats.Raids {
+				if stats.Raids[i].ReceivedAt <= 0 {
+					t.Errorf("raid %d has no receive time", i)
+				}
+				stats.Raids[i].ReceivedAt = 0
+			}
+			if !reflect.DeepEqual(stats, tt.want) {
+				t.Errorf("stats = %+v, want %+v", stats, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionStatsRecordSums(t *testing.T) {
+	stats := NewSessionStats(sessionStartedAt)
+	for _, event := range []interface{}{
+		ChannelCheerEventV1{UserRef: UserRef{UserName: "Viewer"}, Bits: 100},
+		ChannelCheerEventV1{UserRef: UserRef{UserName: "Viewer"}, Bits: 50},
+		ChannelSubscriptionGiftEventV1{UserRef: UserRef{UserName: "Gifter"}, Total: 5},
+		ChannelSubscriptionGiftEventV1{UserRef: UserRef{UserName: "Gifter"}, Total: 1},
+		CharityDonationEventV1{Amount: Amount{Value: 500, DecimalPlaces: 2, Currency: "EUR"}},
+		CharityDonationEventV1{Amount: Amount{Value: 250, DecimalPlaces: 2, Currency: "EUR"}},
+	} {
+		stats.Record("", event, "1")
+	}
+
+	if got := stats.BitsByUser["Viewer"]; got != 150 {
+		t.Errorf("bits = %d, want 150", got)
+	}
+	if got := stats.GiftedSubsByGifter["Gifter"]; got != 6 {
+		t.Errorf("gifted subs = %d, want 6", got)
+	}
+	if got, want := stats.DonationsByCurrency["EUR"], (DonationTotal{Value: 750, DecimalPlaces: 2}); got != want {
+		t.Errorf("donations = %+v, want %+v", got, want)
+	}
+}
+
+func TestSessionStatsSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats", "session_stats.json")
+
+	stats := statsWith(func(s *SessionStats) {
+		s.StreamStartedAt = sessionStartedAt.Add(-time.Hour).Unix()
+		s.Followers = []string{"Viewer"}
+		s.SubsByTier["1000"] = 2
+		s.BitsByUser[""] = 100
+		s.Raids = []RaidStat{{FromUserName: "Raider", Viewers: 42, ReceivedAt: 1704067200}}
+		s.DonationsByCurrency["USD"] = DonationTotal{Value: 500, DecimalPlaces: 2}
+	})
+	if err := stats.Save(path); err != nil {
+		t.Fatalf("Save returned %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file is left behind: %v", err)
+	}
+
+	// saving again replaces the file
+	stats.Followers = append(stats.Followers, "Second")
+	if err := stats.Save(path); err != nil {
+		t.Fatalf("Save returned %v", err)
+	}
+
+	loaded, err := LoadSessionStats(path)
+	if err != nil {
+		t.Fatalf("LoadSessionStats returned %v", err)
+	}
+	if !reflect.DeepEqual(loaded, stats) {
+		t.Errorf("loaded %+v, want %+v", loaded, stats)
+	}
+}
+
+func TestLoadSessionStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // no file if empty
+		wantErr bool
+	}{
+		{name: "missing file"},
+		{name: "corrupt file", content: `{"started_at":`, wantErr: true},
+		{name: "wrong types", content: `{"followers":5}`, wantErr: true},
+		{name: "maps missing from file", content: `{"started_at":1704067200}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session_stats.json")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stats, err := LoadSessionStats(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadSessionStats returned error %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if stats != nil {
+					t.Errorf("LoadSessionStats returned %+v with error", stats)
+				}
+				return
+			}
+
+			// empty stats have to be usable right away
+			if stats.SubsByTier == nil || stats.GiftedSubsByGifter == nil || stats.BitsByUser == nil ||
+				stats.RedemptionsByReward == nil || stats.DonationsByCurrency == nil {
+				t.Errorf("LoadSessionStats returned stats without maps: %+v", stats)
+			}
+			if stats.StartedAt <= 0 {
+				t.Errorf("LoadSessionStats returned stats without a start time: %+v", stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package lib
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+type sampleEvent struct {
+	version string
+	build   func(broadcaster BroadcasterRef, now time.Time) map[string]interface{}
+}
+
+func sampleUser() map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    "12345",
+		"user_login": "simulated_user",
+		"user_name":  "Simulated_User",
+	}
+}
+
+func sampleModerator() map[string]interface{} {
+	return map[string]interface{}{
+		"moderator_user_id":    "54321",
+		"moderator_user_login": "simulated_mod",
+		"moderator_user_name":  "Simulated_Mod",
+	}
+}
+
+func sampleBroadcaster(broadcaster BroadcasterRef) map[string]interface{} {
+	return map[string]interface{}{
+		"broadcaster_user_id":    broadcaster.BroadcasterUserID,
+		"broadcaster_user_login": broadcaster.BroadcasterUserLogin,
+		"broadcaster_user_name":  broadcaster.BroadcasterUserName,
+	}
+}
+
+func merged(parts ...map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, part := range parts {
+		for key, value := range part {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+func samplePoll(status string, withVotes bool, now time.Time) map[string]interface{} {
+	votes := 0
+	if withVotes {
+		votes = 12
+	}
+
+	poll := map[string]interface{}{
+		"id":    "1243456",
+		"title": "Aren't shoes just really hard socks?",
+		"choices": []interface{}{
+			map[string]interface{}{"id": "123", "title": "Blue", "bits_votes": votes, "channel_points_votes": votes, "votes": votes},
+			map[string]interface{}{"id": "124", "title": "Yellow", "bits_votes": 0, "channel_points_votes": votes / 2, "votes": votes / 2},
+		},
+		"started_at": now.Add(-time.Minute).Format(time.RFC3339),
+		"ends_at":    now.Add(time.Minute).Format(time.RFC3339),
+	}
+	if status != "" {
+		poll["status"] = status
+		poll["ended_at"] = now.Format(time.RFC3339)
+		delete(poll, "ends_at")
+	}
+
+	return poll
+}
+
+func samplePrediction(status string, now time.Time) map[string]interface{} {
+	prediction := map[string]interface{}{
+		"id":    "1243456",
+		"title": "Aren't shoes just really hard socks?",
+		"outcomes": []interface{}{
+			map[string]interface{}{
+				"id": "1243456", "title": "Yeah!", "color": "blue", "users": 1, "channel_points": 500,
+				"top_predictors": []interface{}{
+					map[string]interface{}{
+						"user_id": "12345", "user_login": "simulated_user", "user_name": "Simulated_User",
+						"channel_points_used": 500, "channel_points_won": 1000,
+					},
+				},
+			},
+			map[string]interface{}{"id": "2243456", "title": "No!", "color": "pink", "users": 0, "channel_points": 0},
+		},
+		"started_at": now.Add(-time.Minute).Format(time.RFC3339),
+		"locks_at":   now.Add(time.Minute).Format(time.RFC3339),
+	}
+
+	switch status {
+	case "locked":
+		prediction["locked_at"] = now.Format(time.RFC3339)
+	case "resolved":
+		prediction["status"] = status
+		prediction["winning_outcome_id"] = "1243456"
+		prediction["ended_at"] = now.Format(time.RFC3339)
+	}
+
+	return prediction
+}
+
+func sampleRedemption(status string, now time.Time) map[string]interface{} {
+	return merged(sampleUser(), map[string]interface{}{
+		"id":         "17fa2df1-ad76-4804-bfa5-a40ef63efe63",
+		"user_input": "pogchamp",
+		"status":     status,
+		"reward": map[string]interface{}{
+			"id": "92af127c-7326-4483-a52b-b0da0be61c01", "title": "Hydrate", "cost": 100, "prompt": "Drink some water",
+		},
+		"redeemed_at": now.Format(time.RFC3339),
+	})
+}
+
+func sampleReward() map[string]interface{} {
+	return map[string]interface{}{
+		"id": "92af127c-7326-4483-a52b-b0da0be61c01", "title": "Hydrate", "prompt": "Drink some water", "cost": 100,
+		"background_color": "#9146FF", "is_enabled": true, "is_paused": false, "is_in_stock": true,
+		"is_user_input_required": false,
+	}
+}
+
+func sampleCharityCampaign(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  "123-abc-456-def",
+		"charity_name":        "Example name",
+		"charity_description": "Example description",
+		"charity_logo":        "https://abc.cloudfront.net/ppgf/1000/100.png",
+		"charity_website":     "https://www.example.com",
+		"current_amount":      map[string]interface{}{"value": 260000, "decimal_places": 2, "currency": "USD"},
+		"target_amount":       map[string]interface{}{"value": 1500000, "decimal_places": 2, "currency": "USD"},
+		"started_at":          now.Add(-time.Hour).Format(time.RFC3339),
+	}
+}
+
+func targetUser() map[string]interface{} {
+	return map[string]interface{}{
+		"target_user_id":    "12345",
+		"target_user_login": "simulated_user",
+		"target_user_name":  "Simulated_User",
+	}
+}
+
+var sampleEvents = map[string]sampleEvent{
+	helix.EventSubTypeChannelFollow: {"2", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), map[string]interface{}{"followed_at": now.Format(time.RFC3339)})
+	}},
+	helix.EventSubTypeChannelSubscription: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), map[string]interface{}{"tier": "1000", "is_gift": false})
+	}},
+	helix.EventSubTypeChannelSubscriptionMessage: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), map[string]interface{}{
+			"tier": "1000",
+			"message": map[string]interface{}{
+				"text":   "Love the stream! FevziGG",
+				"emotes": []interface{}{map[string]interface{}{"begin": 17, "end": 23, "id": "302976485"}},
+			},
+			"cumulative_months": 15, "streak_months": 1, "duration_months": 6,
+		})
+	}},
+	helix.EventSubTypeChannelSubscriptionGift: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), map[string]interface{}{
+			"total": 5, "tier": "1000", "cumulative_total": 284, "is_anonymous": false,
+		})
+	}},
+	helix.EventSubTypeChannelCheer: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), map[string]interface{}{
+			"is_anonymous": false, "message": "Cheer100 pogchamp", "bits": 100,
+		})
+	}},
+	helix.EventSubTypeChannelRaid: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"from_broadcaster_user_id":    "12345",
+			"from_broadcaster_user_login": "simulated_user",
+			"from_broadcaster_user_name":  "Simulated_User",
+			"to_broadcaster_user_id":      b.BroadcasterUserID,
+			"to_broadcaster_user_login":   b.BroadcasterUserLogin,
+			"to_broadcaster_user_name":    b.BroadcasterUserName,
+			"viewers":                     9001,
+		}
+	}},
+	helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleRedemption("unfulfilled", now))
+	}},
+	helix.EventSubTypeChannelPointsCustomRewardRedemptionUpdate: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleRedemption("fulfilled", now))
+	}},
+	helix.EventSubTypeChannelPointsCustomRewardAdd: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleReward())
+	}},
+	helix.EventSubTypeChannelPointsCustomRewardUpdate: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleReward())
+	}},
+	helix.EventSubTypeChannelPointsCustomRewardRemove: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleReward())
+	}},
+	EventSubTypeChannelPointsAutomaticRewardRedemptionAdd: {"2", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), map[string]interface{}{
+			"id": "f024099a-e0f4-4bc8-ba2b-d4e6ac40e4d6",
+			"reward": map[string]interface{}{
+				"type": "gigantify_an_emote", "channel_points": 300,
+				"emote": map[string]interface{}{"id": "25", "name": "Kappa"},
+			},
+			"message": map[string]interface{}{
+				"text": "Kappa",
+				"fragments": []interface{}{
+					map[string]interface{}{"type": "emote", "text": "Kappa", "emote": map[string]interface{}{"id": "25", "emote_set_id": "0"}},
+				},
+			},
+			"redeemed_at": now.Format(time.RFC3339),
+		})
+	}},
+	helix.EventSubShoutoutCreate: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleModerator(), map[string]interface{}{
+			"to_broadcaster_user_id":    "12345",
+			"to_broadcaster_user_login": "simulated_user",
+			"to_broadcaster_user_name":  "Simulated_User",
+			"viewer_count":              860,
+			"started_at":                now.Format(time.RFC3339),
+			"cooldown_ends_at":          now.Add(2 * time.Minute).Format(time.RFC3339),
+			"target_cooldown_ends_at":   now.Add(time.Hour).Format(time.RFC3339),
+		})
+	}},
+	helix.EventSubShoutoutReceive: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), map[string]interface{}{
+			"from_broadcaster_user_id":    "12345",
+			"from_broadcaster_user_login": "simulated_user",
+			"from_broadcaster_user_name":  "Simulated_User",
+			"viewer_count":                860,
+			"started_at":                  now.Format(time.RFC3339),
+		})
+	}},
+	helix.EventSubTypeCharityDonation: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), map[string]interface{}{
+			"id": "a1b2c3-aabb-4455-d1e2f3", "campaign_id": "123-abc-456-def",
+			"charity_name": "Example name", "charity_description": "Example description",
+			"charity_logo": "https://abc.cloudfront.net/ppgf/1000/100.png", "charity_website": "https://www.example.com",
+			"amount": map[string]interface{}{"value": 10000, "decimal_places": 2, "currency": "USD"},
+		})
+	}},
+	helix.EventSubTypeCharityStart: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleCharityCampaign(now))
+	}},
+	helix.EventSubTypeCharityProgress: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleCharityCampaign(now))
+	}},
+	helix.EventSubTypeCharityStop: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), sampleCharityCampaign(now), map[string]interface{}{"stopped_at": now.Format(time.RFC3339)})
+	}},
+	helix.EventSubTypeChannelPollBegin: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), samplePoll("", false, now))
+	}},
+	helix.EventSubTypeChannelPollProgress: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), samplePoll("", true, now))
+	}},
+	helix.EventSubTypeChannelPollEnd: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), samplePoll("completed", true, now))
+	}},
+	helix.EventSubTypeChannelPredictionBegin: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), samplePrediction("", now))
+	}},
+	helix.EventSubTypeChannelPredictionProgress: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), samplePrediction("", now))
+	}},
+	helix.EventSubTypeChannelPredictionLock: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), samplePrediction("locked", now))
+	}},
+	helix.EventSubTypeChannelPredictionEnd: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), samplePrediction("resolved", now))
+	}},
+	helix.EventSubTypeStreamOnline: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), map[string]interface{}{
+			"id": "9001", "type": "live", "started_at": now.Format(time.RFC3339),
+		})
+	}},
+	helix.EventSubTypeStreamOffline: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return sampleBroadcaster(b)
+	}},
+	helix.EventSubTypeChannelUpdate: {"2", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), map[string]interface{}{
+			"title": "Simulated stream title", "language": "en",
+			"category_id": "509658", "category_name": "Just Chatting",
+			"content_classification_labels": []interface{}{},
+		})
+	}},
+	helix.EventSubTypeChannelBan: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), sampleModerator(), map[string]interface{}{
+			"reason": "Offensive language", "banned_at": now.Format(time.RFC3339),
+			"ends_at": now.Add(10 * time.Minute).Format(time.RFC3339), "is_permanent": false,
+		})
+	}},
+	helix.EventSubTypeChannelUnban: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b), sampleModerator())
+	}},
+	helix.EventSubTypeModeratorAdd: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b))
+	}},
+	helix.EventSubTypeModeratorRemove: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b))
+	}},
+	EventSubTypeChannelVIPAdd: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b))
+	}},
+	EventSubTypeChannelVIPRemove: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleUser(), sampleBroadcaster(b))
+	}},
+	helix.EventSubTypeChannelChatClear: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return sampleBroadcaster(b)
+	}},
+	helix.EventSubTypeChannelChatClearUserMessages: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), targetUser())
+	}},
+	helix.EventSubTypeChannelChatMessageDelete: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), targetUser(), map[string]interface{}{"message_id": "ab24e0b0-2260-4bac-94e4-05eedd4ecd0e"})
+	}},
+	helix.EventSubTypeChannelChatNotification: {"1", func(b BroadcasterRef, now time.Time) map[string]interface{} {
+		return merged(sampleBroadcaster(b), map[string]interface{}{
+			"chatter_user_id":      "12345",
+			"chatter_user_login":   "simulated_user",
+			"chatter_user_name":    "Simulated_User",
+			"chatter_is_anonymous": false,
+			"color":                "#9146FF",
+			"system_message":       "Simulated_User subscribed at Tier 1. They've subscribed for 15 months!",
+			"message_id":           "d62235c8-47ff-a4f4-84e8-5a29a65a9c03",
+			"message":              map[string]interface{}{"text": "", "fragments": []interface{}{}},
+			"notice_type":          "resub",
+			"resub": map[string]interface{}{
+				"cumulative_months": 15, "duration_months": 1, "streak_months": 1, "sub_tier": "1000",
+				"is_prime": false, "is_gift": false, "gifter_is_anonymous": false,
+			},
+		})
+	}},
+}
+
+// SimulatedMessage builds a notification for eventType like twitch would send it. Values of overrides
+// replace the sample values, nested dictionaries are merged.
+func SimulatedMessage(eventType string, broadcaster BroadcasterRef, overrides map[string]interface{}) (TwitchMessage, error) {
+	sample, ok := sampleEvents[eventType]
+	if !ok {
+		return TwitchMessage{}, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	now := time.Now().UTC()
+	event := mergeOverrides(sample.build(broadcaster, now), overrides)
+
+	rawEvent, err := json.Marshal(event)
+	if err != nil {
+		return TwitchMessage{}, fmt.Errorf("unable to encode simulated event: %w", err)
+	}
+
+	var msg TwitchMessage
+	msg.Metadata.ID = randomID()
+	msg.Metadata.Type = "notification"
+	msg.Metadata.Timestamp = now.Format(time.RFC3339Nano)
+	msg.Payload.Subscription = &twitchSubscriptionPayload{
+		ID:      "simulated",
+		Type:    eventType,
+		Version: sample.version,
+	}
+	msg.Payload.Event = rawEvent
+
+	return msg, nil
+}
+
+func mergeOverrides(event map[string]interface{}, overrides map[string]interface{}) map[string]interface{} {
+	for key, value := range overrides {
+		overrideMap, isMap := value.(map[string]interface{})
+		eventMap, eventIsMap := event[key].(map[string]interface{})
+		if isMap && eventIsMap {
+			event[key] = mergeOverrides(eventMap, overrideMap)
+			continue
+		}
+
+		event[key] = value
+	}
+
+	return event
+}
+
+func randomID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("simulated-%d", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(id)
+}
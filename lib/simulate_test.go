@@ -0,0 +1,144 @@
+package lib
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+func TestMergeOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     map[string]interface{}
+		overrides map[string]interface{}
+		want      map[string]interface{}
+	}{
+		{
+			name:      "no overrides",
+			event:     map[string]interface{}{"bits": 100},
+			overrides: nil,
+			want:      map[string]interface{}{"bits": 100},
+		},
+		{
+			name:      "replaces values",
+			event:     map[string]interface{}{"bits": 100, "message": "Cheer100"},
+			overrides: map[string]interface{}{"bits": 500},
+			want:      map[string]interface{}{"bits": 500, "message": "Cheer100"},
+		},
+		{
+			name:      "adds new keys",
+			event:     map[string]interface{}{"bits": 100},
+			overrides: map[string]interface{}{"is_anonymous": true},
+			want:      map[string]interface{}{"bits": 100, "is_anonymous": true},
+		},
+		{
+			name: "merges nested dictionaries",
+			event: map[string]interface{}{
+				"reward": map[string]interface{}{"title": "Hydrate", "cost": 500},
+			},
+			overrides: map[string]interface{}{
+				"reward": map[string]interface{}{"cost": 1000},
+			},
+			want: map[string]interface{}{
+				"reward": map[string]interface{}{"title": "Hydrate", "cost": 1000},
+			},
+		},
+		{
+			name: "merges deeply nested dictionaries",
+			event: map[string]interface{}{
+				"resub": map[string]interface{}{
+					"tier": map[string]interface{}{"id": "1000", "name": "Tier 1"},
+				},
+			},
+			overrides: map[string]interface{}{
+				"resub": map[string]interface{}{
+					"tier": map[string]interface{}{"id": "3000"},
+				},
+			},
+			want: map[string]interface{}{
+				"resub": map[string]interface{}{
+					"tier": map[string]interface{}{"id": "3000", "name": "Tier 1"},
+				},
+			},
+		},
+		{
+			name:      "replaces a dictionary with a value",
+			event:     map[string]interface{}{"reward": map[string]interface{}{"title": "Hydrate"}},
+			overrides: map[string]interface{}{"reward": nil},
+			want:      map[string]interface{}{"reward": nil},
+		},
+		{
+			name:      "replaces a value with a dictionary",
+			event:     map[string]interface{}{"reward": "Hydrate"},
+			overrides: map[string]interface{}{"reward": map[string]interface{}{"title": "Stretch"}},
+			want:      map[string]interface{}{"reward": map[string]interface{}{"title": "Stretch"}},
+		},
+		{
+			name:      "replaces arrays",
+			event:     map[string]interface{}{"choices": []interface{}{"a", "b", "c"}},
+			overrides: map[string]interface{}{"choices": []interface{}{"d"}},
+			want:      map[string]interface{}{"choices": []interface{}{"d"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeOverrides(tt.event, tt.overrides)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeOverrides = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulatedMessage(t *testing.T) {
+	broadcaster := BroadcasterRef{
+		BroadcasterUserID:    "1",
+		BroadcasterUserLogin: "streamer",
+		BroadcasterUserName:  "Streamer",
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := SimulatedMessage("channel.unknown", broadcaster, nil)
+		if !errors.Is(err, ErrUnknownEventType) {
+			t.Errorf("SimulatedMessage returned %v, want %v", err, ErrUnknownEventType)
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		msg, err := SimulatedMessage(helix.EventSubTypeChannelCheer, broadcaster, map[string]interface{}{"bits": 1000})
+		if err != nil {
+			t.Fatalf("SimulatedMessage returned %v", err)
+		}
+		if msg.Metadata.Type != "notification" || msg.Metadata.ID == "" {
+			t.Errorf("metadata = %+v, want a notification with an id", msg.Metadata)
+		}
+
+		subscription := msg.Payload.Subscription
+		event, err := DecodeEvent(subscription.Type, subscription.Version, msg.Payload.Event)
+		if err != nil {
+			t.Fatalf("DecodeEvent returned %v", err)
+		}
+		cheer := event.(ChannelCheerEventV1)
+		if cheer.Bits != 1000 || cheer.UserLogin != "simulated_user" {
+			t.Errorf("event = %+v, want 1000 bits from simulated_user", cheer)
+		}
+	})
+
+	// every sample has to decode into the struct of its type
+	for eventType := range sampleEvents {
+		t.Run(eventType, func(t *testing.T) {
+			msg, err := SimulatedMessage(eventType, broadcaster, nil)
+			if err != nil {
+				t.Fatalf("SimulatedMessage returned %v", err)
+			}
+
+			subscription := msg.Payload.Subscription
+			if _, err := DecodeEvent(subscription.Type, subscription.Version, msg.Payload.Event); err != nil {
+				t.Errorf("DecodeEvent returned %v", err)
+			}
+		})
+	}
+}
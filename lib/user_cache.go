@@ -0,0 +1,360 @@
+package lib
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// helixBatchSize is the max number of ids or logins helix accepts per request.
+const helixBatchSize = 100
+
+// userCacheBatchWait is how long a lookup waits for others to share a request with.
+const userCacheBatchWait = 10 * time.Millisecond
+
+// UserCache sits in front of the helix client for user and channel lookups. Entries expire after ttl
+// and the least recently used entries are dropped once a cache grows over maxEntries. Lookups that
+// miss are batched into one request and lookups of keys that are already requested wait for that
+// request instead of sending their own.
+type UserCache struct {
+	client *helix.Client
+	ttl    time.Duration
+
+	lock      sync.Mutex
+	users     *lruCache[helix.User]
+	loginToID map[string]string
+	channels  *lruCache[helix.ChannelInformation]
+
+	userIDs    *batcher
+	userLogins *batcher
+	channelIDs *batcher
+}
+
+func NewUserCache(client *helix.Client, ttl time.Duration, maxEntries int) *UserCache {
+	c := &UserCache{
+		client:    client,
+		ttl:       ttl,
+		loginToID: make(map[string]string),
+		channels:  newLRUCache[helix.ChannelInformation](maxEntries, nil),
+	}
+	c.users = newLRUCache(maxEntries, func(id string, user helix.User) {
+		if c.loginToID[user.Login] == id {
+			delete(c.loginToID, user.Login)
+		}
+	})
+	c.userIDs = newBatcher(userCacheBatchWait, func(ids []string) error {
+		return c.fetchUsers(&helix.UsersParams{IDs: ids})
+	})
+	c.userLogins = newBatcher(userCacheBatchWait, func(logins []string) error {
+		return c.fetchUsers(&helix.UsersParams{Logins: logins})
+	})
+	c.channelIDs = newBatcher(userCacheBatchWait, c.fetchChannels)
+
+	return c
+}
+
+// GetUsersByID returns the users keyed by ID. Unknown IDs are missing from the result.
+func (c *UserCache) GetUsersByID(ids []string) (map[string]helix.User, error) {
+	result, missing := c.cachedUsers(ids)
+	if len(missing) <= 0 {
+		return result, nil
+	}
+
+	err := c.userIDs.do(missing)
+
+	fetched, _ := c.cachedUsers(missing)
+	for id, user := range fetched {
+		result[id] = user
+	}
+
+	return result, err
+}
+
+// GetUserByLogin returns false if there is no user with that login.
+func (c *UserCache) GetUserByLogin(login string) (helix.User, bool, error) {
+	login = strings.ToLower(login)
+
+	if user, ok := c.cachedUserByLogin(login); ok {
+		return user, true, nil
+	}
+
+	if err := c.userLogins.do([]string{login}); err != nil {
+		return helix.User{}, false, err
+	}
+
+	user, ok := c.cachedUserByLogin(login)
+	return user, ok, nil
+}
+
+// GetChannels returns the channel information keyed by broadcaster ID.
+func (c *UserCache) GetChannels(broadcasterIDs []string) (map[string]helix.ChannelInformation, error) {
+	result, missing := c.cachedChannels(broadcasterIDs)
+	if len(missing) <= 0 {
+		return result, nil
+	}
+
+	err := c.channelIDs.do(missing)
+
+	fetched, _ := c.cachedChannels(missing)
+	for id, channel := range fetched {
+		result[id] = channel
+	}
+
+	return result, err
+}
+
+func (c *UserCache) cachedUsers(ids []string) (map[string]helix.User, []string) {
+	result := make(map[string]helix.User, len(ids))
+	var missing []string
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for _, id := range ids {
+		if user, ok := c.users.get(id, now); ok {
+			result[id] = user
+			continue
+		}
+		missing = append(missing, id)
+	}
+
+	return result, missing
+}
+
+func (c *UserCache) cachedUserByLogin(login string) (helix.User, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id, ok := c.loginToID[login]
+	if !ok {
+		return helix.User{}, false
+	}
+
+	return c.users.get(id, time.Now())
+}
+
+func (c *UserCache) cachedChannels(broadcasterIDs []string) (map[string]helix.ChannelInformation, []string) {
+	result := make(map[string]helix.ChannelInformation, len(broadcasterIDs))
+	var missing []string
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	for _, id := range broadcasterIDs {
+		if channel, ok := c.channels.get(id, now); ok {
+			result[id] = channel
+			continue
+		}
+		missing = append(missing, id)
+	}
+
+	return result, missing
+}
+
+func (c *UserCache) fetchUsers(params *helix.UsersParams) error {
+	userResp, err := c.client.GetUsers(params)
+	if err != nil {
+		return err
+	}
+	if userResp.ErrorMessage != "" {
+		return fmt.Errorf("%d %s", userResp.StatusCode, userResp.ErrorMessage)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	for _, user := range userResp.Data.Users {
+		c.users.add(user.ID, user, expiresAt)
+		c.loginToID[user.Login] = user.ID
+	}
+
+	return nil
+}
+
+func (c *UserCache) fetchChannels(broadcasterIDs []string) error {
+	channelResp, err := c.client.GetChannelInformation(&helix.GetChannelInformationParams{
+		BroadcasterIDs: broadcasterIDs,
+	})
+	if err != nil {
+		return err
+	}
+	if channelResp.ErrorMessage != "" {
+		return fmt.Errorf("%d %s", channelResp.StatusCode, channelResp.ErrorMessage)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	for _, channel := range channelResp.Data.Channels {
+		c.channels.add(channel.BroadcasterID, channel, expiresAt)
+	}
+
+	return nil
+}
+
+type lruEntry[T any] struct {
+	key       string
+	value     T
+	expiresAt time.Time
+}
+
+// lruCache is not safe for concurrent use. onEvict is called for entries dropped to make room or
+// because they expired.
+type lruCache[T any] struct {
+	maxEntries int
+	onEvict    func(key string, value T)
+
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+func newLRUCache[T any](maxEntries int, onEvict func(key string, value T)) *lruCache[T] {
+	return &lruCache[T]{
+		maxEntries: maxEntries,
+		onEvict:    onEvict,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *lruCache[T]) get(key string, now time.Time) (T, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	entry := element.Value.(*lruEntry[T])
+	if !now.Before(entry.expiresAt) {
+		c.remove(element)
+		var zero T
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *lruCache[T]) add(key string, value T, expiresAt time.Time) {
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry[T])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[T]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lruCache[T]) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry[T])
+	delete(c.entries, entry.key)
+	if c.onEvict != nil {
+		c.onEvict(entry.key, entry.value)
+	}
+}
+
+type batchCall struct {
+	done chan struct{}
+	err  error
+}
+
+// batcher collects keys for wait and fetches them with one call of fetch, at most helixBatchSize
+// keys at a time. Keys that are already queued or being fetched are not fetched again.
+type batcher struct {
+	wait  time.Duration
+	fetch func(keys []string) error
+
+	lock     sync.Mutex
+	inFlight map[string]*batchCall
+	queued   []string
+	call     *batchCall
+}
+
+func newBatcher(wait time.Duration, fetch func(keys []string) error) *batcher {
+	return &batcher{
+		wait:     wait,
+		fetch:    fetch,
+		inFlight: make(map[string]*batchCall),
+	}
+}
+
+// do blocks until every key was fetched and returns the first error of the calls it waited for.
+func (b *batcher) do(keys []string) error {
+	calls := make(map[*batchCall]bool)
+
+	b.lock.Lock()
+	for _, key := range keys {
+		if call, ok := b.inFlight[key]; ok {
+			calls[call] = true
+			continue
+		}
+
+		if b.call == nil {
+			call := &batchCall{done: make(chan struct{})}
+			b.call = call
+			time.AfterFunc(b.wait, func() { b.flush(call) })
+		}
+		b.queued = append(b.queued, key)
+		b.inFlight[key] = b.call
+		calls[b.call] = true
+
+		if len(b.queued) >= helixBatchSize {
+			go b.run(b.call, b.queued)
+			b.call = nil
+			b.queued = nil
+		}
+	}
+	b.lock.Unlock()
+
+	var err error
+	for call := range calls {
+		<-call.done
+		if call.err != nil && err == nil {
+			err = call.err
+		}
+	}
+
+	return err
+}
+
+// flush runs call unless it already ran because it was full.
+func (b *batcher) flush(call *batchCall) {
+	b.lock.Lock()
+	if b.call != call {
+		b.lock.Unlock()
+		return
+	}
+	keys := b.queued
+	b.call = nil
+	b.queued = nil
+	b.lock.Unlock()
+
+	b.run(call, keys)
+}
+
+func (b *batcher) run(call *batchCall, keys []string) {
+	call.err = b.fetch(keys)
+
+	b.lock.Lock()
+	for _, key := range keys {
+		if b.inFlight[key] == call {
+			delete(b.inFlight, key)
+		}
+	}
+	b.lock.Unlock()
+
+	close(call.done)
+}
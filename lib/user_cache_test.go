@@ -0,0 +1,219 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	type step struct {
+		add string
+		get string
+		// expired adds the entry with an expiry in the past
+		expired bool
+	}
+
+	tests := []struct {
+		name        string
+		maxEntries  int
+		steps       []step
+		wantKeys    []string
+		wantEvicted []string
+	}{
+		{
+			name:       "keeps entries up to max",
+			maxEntries: 3,
+			steps:      []step{{add: "a"}, {add: "b"}, {add: "c"}},
+			wantKeys:   []string{"a", "b", "c"},
+		},
+		{
+			name:        "evicts least recently added",
+			maxEntries:  2,
+			steps:       []step{{add: "a"}, {add: "b"}, {add: "c"}},
+			wantKeys:    []string{"b", "c"},
+			wantEvicted: []string{"a"},
+		},
+		{
+			name:        "get marks an entry as used",
+			maxEntries:  2,
+			steps:       []step{{add: "a"}, {add: "b"}, {get: "a"}, {add: "c"}},
+			wantKeys:    []string{"a", "c"},
+			wantEvicted: []string{"b"},
+		},
+		{
+			name:        "adding an existing key marks it as used",
+			maxEntries:  2,
+			steps:       []step{{add: "a"}, {add: "b"}, {add: "a"}, {add: "c"}},
+			wantKeys:    []string{"a", "c"},
+			wantEvicted: []string{"b"},
+		},
+		{
+			name:        "get drops expired entries",
+			maxEntries:  2,
+			steps:       []step{{add: "a", expired: true}, {add: "b"}, {get: "a"}},
+			wantKeys:    []string{"b"},
+			wantEvicted: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evicted []string
+			cache := newLRUCache(tt.maxEntries, func(key string, value int) {
+				evicted = append(evicted, key)
+			})
+
+			for i, step := range tt.steps {
+				if step.add != "" {
+					expiresAt := later
+					if step.expired {
+						expiresAt = now.Add(-time.Second)
+					}
+					cache.add(step.add, i, expiresAt)
+				}
+				if step.get != "" {
+					cache.get(step.get, now)
+				}
+			}
+
+			var keys []string
+			for key := range cache.entries {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if !reflect.DeepEqual(evicted, tt.wantEvicted) {
+				t.Errorf("evicted = %v, want %v", evicted, tt.wantEvicted)
+			}
+			if cache.order.Len() != len(cache.entries) {
+				t.Errorf("order has %d entries, map has %d", cache.order.Len(), len(cache.entries))
+			}
+		})
+	}
+}
+
+func TestLRUCacheGet(t *testing.T) {
+	now := time.Now()
+	cache := newLRUCache[string](2, nil)
+	cache.add("a", "first", now.Add(time.Hour))
+	cache.add("a", "second", now.Add(time.Hour))
+
+	value, ok := cache.get("a", now)
+	if !ok || value != "second" {
+		t.Errorf("get(a) = %q, %v, want second, true", value, ok)
+	}
+
+	if _, ok := cache.get("missing", now); ok {
+		t.Error("get(missing) found an entry")
+	}
+	if _, ok := cache.get("a", now.Add(2*time.Hour)); ok {
+		t.Error("get(a) found an expired entry")
+	}
+}
+
+func TestBatcherSplitsBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      int
+		wantSizes []int
+	}{
+		{name: "single key", keys: 1, wantSizes: []int{1}},
+		{name: "full batch", keys: helixBatchSize, wantSizes: []int{helixBatchSize}},
+		{name: "one over", keys: helixBatchSize + 1, wantSizes: []int{helixBatchSize, 1}},
+		{name: "several batches", keys: 2*helixBatchSize + 50, wantSizes: []int{helixBatchSize, helixBatchSize, 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lock sync.Mutex
+			var sizes []int
+			b := newBatcher(time.Millisecond, func(keys []string) error {
+				lock.Lock()
+				sizes = append(sizes, len(keys))
+				lock.Unlock()
+				return nil
+			})
+
+			keys := make([]string, tt.keys)
+			for i := range keys {
+				keys[i] = fmt.Sprint(i)
+			}
+			if err := b.do(keys); err != nil {
+				t.Fatalf("do returned %v", err)
+			}
+
+			sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+			if !reflect.DeepEqual(sizes, tt.wantSizes) {
+				t.Errorf("batch sizes = %v, want %v", sizes, tt.wantSizes)
+			}
+		})
+	}
+}
+
+func TestBatcherSharesRequests(t *testing.T) {
+	var lock sync.Mutex
+	fetched := make(map[string]int)
+	release := make(chan struct{})
+	b := newBatcher(10*time.Millisecond, func(keys []string) error {
+		<-release
+		lock.Lock()
+		defer lock.Unlock()
+		for _, key := range keys {
+			fetched[key]++
+		}
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for _, keys := range [][]string{{"a"}, {"a", "b"}, {"b"}, {"c"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.do(keys); err != nil {
+				t.Errorf("do(%v) returned %v", keys, err)
+			}
+		}()
+	}
+
+	// let every lookup join before the first request finishes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	want := map[string]int{"a": 1, "b": 1, "c": 1}
+	if !reflect.DeepEqual(fetched, want) {
+		t.Errorf("fetched = %v, want %v", fetched, want)
+	}
+}
+
+func TestBatcherReturnsError(t *testing.T) {
+	fetchErr := errors.New("unavailable")
+	calls := 0
+	b := newBatcher(time.Millisecond, func(keys []string) error {
+		calls++
+		return fetchErr
+	})
+
+	if err := b.do([]string{"a"}); !errors.Is(err, fetchErr) {
+		t.Errorf("do returned %v, want %v", err, fetchErr)
+	}
+
+	// failed keys are requested again on the next lookup
+	if err := b.do([]string{"a"}); !errors.Is(err, fetchErr) {
+		t.Errorf("do returned %v, want %v", err, fetchErr)
+	}
+	if calls != 2 {
+		t.Errorf("fetch was called %d times, want 2", calls)
+	}
+}
@@ -1,6 +1,7 @@
 package lib
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -14,18 +15,20 @@ type (
 		ReconnectURL string `json:"reconnect_url"`
 	}
 	twitchSubscriptionPayload struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
-		Type   string `json:"type"`
+		ID      string `json:"id"`
+		Status  string `json:"status"`
+		Type    string `json:"type"`
+		Version string `json:"version"`
 	}
 	twitchPayload struct {
 		Session      *twitchSessionPayload      `json:"session"`
 		Subscription *twitchSubscriptionPayload `json:"subscription"`
-		Event        map[string]interface{}     `json:"event"`
+		Event        json.RawMessage            `json:"event"`
 	}
 	twitchMetaData struct {
-		ID   string `json:"message_id"`
-		Type string `json:"message_type"`
+		ID        string `json:"message_id"`
+		Type      string `json:"message_type"`
+		Timestamp string `json:"message_timestamp"`
 	}
 	TwitchMessage struct {
 		Metadata twitchMetaData `json:"metadata"`
@@ -33,7 +36,8 @@ type (
 	}
 )
 
-func Websocket(useDebug bool) (<-chan TwitchMessage, <-chan string) {
+// Websocket connects to EventSub. If recorder is not nil every received frame is recorded.
+func Websocket(useDebug bool, recorder *Recorder) (<-chan TwitchMessage, <-chan string) {
 	twitchEventChan := make(chan TwitchMessage, 1)
 	sessionIDChan := make(chan string, 1)
 	go func() {
@@ -43,7 +47,7 @@ func Websocket(useDebug bool) (<-chan TwitchMessage, <-chan string) {
 		}
 
 		for {
-			newReconnectURL, err := makeConnAndRead(wsConenctURL, twitchEventChan, sessionIDChan)
+			newReconnectURL, err := makeConnAndRead(wsConenctURL, twitchEventChan, sessionIDChan, recorder)
 			if err != nil {
 				panic(err)
 			}
@@ -59,6 +63,7 @@ func Websocket(useDebug bool) (<-chan TwitchMessage, <-chan string) {
 func makeConnAndRead(
 	url string,
 	msgOutChan chan<- TwitchMessage, sessionIDChan chan<- string,
+	recorder *Recorder,
 ) (string, error) {
 	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{})
 	if err != nil {
@@ -67,9 +72,19 @@ func makeConnAndRead(
 
 	defer conn.Close()
 	for {
-		msg := TwitchMessage{}
-		err := conn.ReadJSON(&msg)
+		_, frame, err := conn.ReadMessage()
 		if err != nil {
+			return "", fmt.Errorf("error: reading websocket frame: %w", err)
+		}
+
+		if recorder != nil {
+			if err := recorder.Record(frame); err != nil {
+				LogWarn(err.Error())
+			}
+		}
+
+		msg := TwitchMessage{}
+		if err := json.Unmarshal(frame, &msg); err != nil {
 			return "", fmt.Errorf("error: parsing event JSON: %w", err)
 		}
 
@@ -10,5 +10,28 @@ import (
 func main() {
 	classdb.Register[node.GodotTwitch]()
 	classdb.Register[node.GodotTwitchEmoteStore]()
+
+	classdb.Register[node.TwitchFollowEvent]()
+	classdb.Register[node.TwitchSubscriptionEvent]()
+	classdb.Register[node.TwitchGiftSubEvent]()
+	classdb.Register[node.TwitchCheerEvent]()
+	classdb.Register[node.TwitchRaidEvent]()
+	classdb.Register[node.TwitchRedemptionEvent]()
+	classdb.Register[node.TwitchAutomaticRewardEvent]()
+	classdb.Register[node.TwitchShoutoutEvent]()
+	classdb.Register[node.TwitchDonationEvent]()
+	classdb.Register[node.TwitchBanEvent]()
+	classdb.Register[node.TwitchRoleEvent]()
+	classdb.Register[node.TwitchPollEvent]()
+	classdb.Register[node.TwitchPredictionEvent]()
+	classdb.Register[node.TwitchStreamEvent]()
+	classdb.Register[node.TwitchChannelUpdateEvent]()
+	classdb.Register[node.TwitchChatNotificationEvent]()
+	classdb.Register[node.TwitchChatClearEvent]()
+	classdb.Register[node.TwitchRewardEvent]()
+	classdb.Register[node.TwitchCharityCampaignEvent]()
+	classdb.Register[node.TwitchAlert]()
+	classdb.Register[node.TwitchRule]()
+	classdb.Register[node.TwitchRuleCondition]()
 	startup.Engine()
 }
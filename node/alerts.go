@@ -0,0 +1,156 @@
+package node
+
+import (
+	"graphics.gd/classdb"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/RefCounted"
+)
+
+type TwitchAlert struct {
+	classdb.Extension[TwitchAlert, RefCounted.Instance] `gd:"TwitchAlert"
+		An event waiting in or shown by the alert queue of GodotTwitch`
+
+	ID         int      `gd:"id"`
+	Type       string   `gd:"type"`
+	Priority   int      `gd:"priority"`
+	Duration   Float.X  `gd:"duration"`
+	Recipients []string `gd:"recipients"`
+}
+
+const (
+	AlertTypeFollow          = "follow"
+	AlertTypeSubscription    = "subscription"
+	AlertTypeGiftSub         = "gift_sub"
+	AlertTypeCheer           = "cheer"
+	AlertTypeRaid            = "raid"
+	AlertTypeRedemption      = "redemption"
+	AlertTypeAutomaticReward = "automatic_reward"
+	AlertTypeShoutout        = "shoutout"
+	AlertTypeDonation        = "donation"
+)
+
+type alertSetting struct {
+	priority int
+	duration Float.X
+}
+
+// defaultAlertSettings are used until changed with set_alert_priority and set_alert_duration. Higher
+// priorities are shown first, alerts with the same priority in the order they arrived.
+func defaultAlertSettings() map[string]alertSetting {
+	return map[string]alertSetting{
+		AlertTypeRaid:            {priority: 30, duration: 8},
+		AlertTypeGiftSub:         {priority: 20, duration: 6},
+		AlertTypeDonation:        {priority: 20, duration: 6},
+		AlertTypeSubscription:    {priority: 10, duration: 5},
+		AlertTypeCheer:           {priority: 10, duration: 5},
+		AlertTypeShoutout:        {priority: 10, duration: 5},
+		AlertTypeFollow:          {priority: 0, duration: 3},
+		AlertTypeRedemption:      {priority: 0, duration: 4},
+		AlertTypeAutomaticReward: {priority: 0, duration: 4},
+	}
+}
+
+type queuedAlert struct {
+	alert *TwitchAlert
+	event alertEvent
+}
+
+// pushAlert adds an alert to the queue if use_alert_queue is enabled.
+func (h *GodotTwitch) pushAlert(alertType string, event alertEvent) *TwitchAlert {
+	if !h.UseAlertQueue {
+		return nil
+	}
+
+	setting, ok := h.alertSettings[alertType]
+	if !ok {
+		setting = alertSetting{duration: h.DefaultAlertDuration}
+	}
+
+	h.alertSequence++
+	alert := new(TwitchAlert)
+	alert.ID = h.alertSequence
+	alert.Type = alertType
+	alert.Priority = setting.priority
+	alert.Duration = setting.duration
+
+	h.alertQueue = append(h.alertQueue, &queuedAlert{alert: alert, event: event})
+
+	return alert
+}
+
+func (h *GodotTwitch) handleAlertTick(delta Float.X) {
+	if h.currentAlert != nil {
+		h.currentAlertRemaining -= delta
+		if h.currentAlertRemaining > 0 {
+			return
+		}
+
+		h.finishCurrentAlert()
+	}
+
+	if h.alertsPaused || len(h.alertQueue) <= 0 {
+		return
+	}
+
+	next := 0
+	for i, queued := range h.alertQueue {
+		if queued.alert.Priority > h.alertQueue[next].alert.Priority {
+			next = i
+		}
+	}
+
+	h.currentAlert = h.alertQueue[next]
+	h.currentAlertRemaining = h.currentAlert.alert.Duration
+	h.alertQueue = append(h.alertQueue[:next], h.alertQueue[next+1:]...)
+
+	h.OnAlertStart.Emit(h.currentAlert.alert, h.currentAlert.event.asObject())
+}
+
+func (h *GodotTwitch) finishCurrentAlert() {
+	finished := h.currentAlert
+	h.currentAlert = nil
+	h.currentAlertRemaining = 0
+
+	h.OnAlertFinished.Emit(finished.alert)
+}
+
+// SetAlertDuration sets how many seconds alerts of the given type are shown.
+func (h *GodotTwitch) SetAlertDuration(alertType string, seconds Float.X) {
+	setting := h.alertSettings[alertType]
+	setting.duration = seconds
+	h.alertSettings[alertType] = setting
+}
+
+// SetAlertPriority sets the priority of the given alert type, higher priorities are shown first.
+func (h *GodotTwitch) SetAlertPriority(alertType string, priority int) {
+	setting := h.alertSettings[alertType]
+	setting.priority = priority
+	h.alertSettings[alertType] = setting
+}
+
+// PauseAlerts stops new alerts from starting. A running alert still finishes.
+func (h *GodotTwitch) PauseAlerts() {
+	h.alertsPaused = true
+}
+
+func (h *GodotTwitch) ResumeAlerts() {
+	h.alertsPaused = false
+}
+
+// SkipAlert finishes the running alert right away.
+func (h *GodotTwitch) SkipAlert() {
+	if h.currentAlert == nil {
+		return
+	}
+
+	h.finishCurrentAlert()
+}
+
+// ClearAlerts drops all queued alerts. A running alert is not affected.
+func (h *GodotTwitch) ClearAlerts() {
+	h.alertQueue = nil
+}
+
+func (h *GodotTwitch) GetQueuedAlertCount() int {
+	return len(h.alertQueue)
+}
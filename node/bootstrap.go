@@ -0,0 +1,394 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+	"strings"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// bootstrapStep loads state on startup. The returned update is applied in handleApiUpdateTick, nil
+// means there is nothing to apply.
+type bootstrapStep struct {
+	name  string
+	fetch func(client *helix.Client, broadcasterUserID string) (interface{}, error)
+}
+
+// bootstrapSteps run one after another before the websocket connects. Add a step together with its
+// update type to load more state.
+var bootstrapSteps = []bootstrapStep{
+	{"latest channel follower", fetchLatestFollower},
+	{"latest subscriber", fetchLatestSubscriber},
+	{"custom rewards", fetchRewardCatalog},
+	{"charity campaign", fetchCharityCampaign},
+	{"current stream", fetchStreamState},
+	{"channel information", fetchChannelInfo},
+	{"active poll", fetchActivePoll},
+	{"active prediction", fetchActivePrediction},
+	{"creator goals", fetchGoals},
+	{"hype train", fetchHypeTrain},
+}
+
+func (h *GodotTwitch) bootstrap(client *helix.Client, broadcasterUserID string) {
+	for _, step := range bootstrapSteps {
+		update, err := step.fetch(client, broadcasterUserID)
+		if err != nil {
+			lib.LogErr(fmt.Sprintf("unable to get %s: %s", step.name, err.Error()))
+			continue
+		}
+		if update == nil {
+			continue
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, update)
+		h.apiInfoResponseLock.Unlock()
+	}
+}
+
+// helixError turns error responses into errors, helix only returns errors for failed requests.
+func helixError(resp helix.ResponseCommon) error {
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%d %s", resp.StatusCode, resp.ErrorMessage)
+	}
+
+	return nil
+}
+
+func fetchLatestFollower(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	followerResp, err := client.GetChannelFollows(&helix.GetChannelFollowsParams{
+		BroadcasterID: broadcasterUserID,
+		First:         1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(followerResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+
+	update := LatestFollowerUpdate{Total: followerResp.Data.Total}
+	if len(followerResp.Data.Channels) > 0 {
+		update.Username = followerResp.Data.Channels[0].Username
+	}
+
+	return update, nil
+}
+
+func fetchLatestSubscriber(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	subscribersResp, err := client.GetSubscriptions(&helix.SubscriptionsParams{
+		BroadcasterID: broadcasterUserID,
+		First:         1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(subscribersResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+
+	update := LatestSubscriberUpdate{
+		Total:  subscribersResp.Data.Total,
+		Points: subscribersResp.Data.Points,
+	}
+	if len(subscribersResp.Data.Subscriptions) > 0 {
+		update.Username = subscribersResp.Data.Subscriptions[0].UserName
+	}
+
+	return update, nil
+}
+
+func fetchRewardCatalog(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	rewardsResp, err := client.GetCustomRewards(&helix.GetCustomRewardsParams{
+		BroadcasterID: broadcasterUserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(rewardsResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+
+	rewardUpdate := RewardCatalogUpdate{}
+	for _, customReward := range rewardsResp.Data.ChannelCustomRewards {
+		rewardUpdate.Rewards = append(rewardUpdate.Rewards, Reward{
+			ID:                  customReward.ID,
+			Title:               customReward.Title,
+			Prompt:              customReward.Prompt,
+			Cost:                customReward.Cost,
+			BackgroundColor:     customReward.BackgroundColor,
+			IsEnabled:           customReward.IsEnabled,
+			IsPaused:            customReward.IsPaused,
+			IsInStock:           customReward.IsInStock,
+			IsUserInputRequired: customReward.IsUserInputRequired,
+		})
+	}
+
+	return rewardUpdate, nil
+}
+
+func fetchCharityCampaign(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	charityResp, err := client.GetCharityCampaigns(&helix.CharityCampaignsParams{
+		BroadcasterID: broadcasterUserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(charityResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+	if len(charityResp.Data.Campaigns) <= 0 {
+		return nil, nil
+	}
+
+	charity := charityResp.Data.Campaigns[0]
+	currentValue := int(charity.CurrentAmount.Value)
+	targetValue := int(charity.TargetAmount.Value)
+	decimalPlaces := int(charity.CurrentAmount.DecimalPlaces)
+
+	return CharityCampaignUpdate{CharityCampaign{
+		ID:                 charity.ID,
+		IsActive:           true,
+		CharityName:        charity.Name,
+		CharityDescription: charity.Description,
+		CharityLogoURL:     charity.LogoUrl,
+		CharityWebsite:     charity.WebsiteUrl,
+		CurrentValue:       currentValue,
+		TargetValue:        targetValue,
+		DecimalPlaces:      decimalPlaces,
+		Currency:           charity.CurrentAmount.Currency,
+		CurrentAmount:      minorUnitsToFloat(currentValue, decimalPlaces),
+		TargetAmount:       minorUnitsToFloat(targetValue, decimalPlaces),
+	}}, nil
+}
+
+func fetchStreamState(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	streamsResp, err := client.GetStreams(&helix.StreamsParams{
+		UserIDs: []string{broadcasterUserID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(streamsResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+
+	streamUpdate := StreamStateUpdate{}
+	if len(streamsResp.Data.Streams) > 0 {
+		stream := streamsResp.Data.Streams[0]
+		streamUpdate.IsLive = true
+		streamUpdate.StartedAt = int(stream.StartedAt.Unix())
+	}
+
+	return streamUpdate, nil
+}
+
+func fetchChannelInfo(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	channelResp, err := client.GetChannelInformation(&helix.GetChannelInformationParams{
+		BroadcasterIDs: []string{broadcasterUserID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(channelResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+	if len(channelResp.Data.Channels) <= 0 {
+		return nil, nil
+	}
+
+	channel := channelResp.Data.Channels[0]
+	return ChannelInfoUpdate{
+		Title:        channel.Title,
+		CategoryID:   channel.GameID,
+		CategoryName: channel.GameName,
+	}, nil
+}
+
+// fetchActivePoll only returns the newest poll if it is still running.
+func fetchActivePoll(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	pollsResp, err := client.GetPolls(&helix.PollsParams{
+		BroadcasterID: broadcasterUserID,
+		First:         "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(pollsResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+	if len(pollsResp.Data.Polls) <= 0 || pollsResp.Data.Polls[0].Status != "ACTIVE" {
+		return nil, nil
+	}
+
+	return ActivePollUpdate{pollFromHelix(pollsResp.Data.Polls[0])}, nil
+}
+
+// fetchActivePrediction only returns the newest prediction if it is running or locked.
+func fetchActivePrediction(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	predictionsResp, err := client.GetPredictions(&helix.PredictionsParams{
+		BroadcasterID: broadcasterUserID,
+		First:         "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(predictionsResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+	if len(predictionsResp.Data.Predictions) <= 0 {
+		return nil, nil
+	}
+
+	prediction := predictionsResp.Data.Predictions[0]
+	if prediction.Status != "ACTIVE" && prediction.Status != "LOCKED" {
+		return nil, nil
+	}
+
+	return ActivePredictionUpdate{predictionFromHelix(prediction)}, nil
+}
+
+func fetchGoals(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	goalsResp, err := client.GetCreatorGoals(&helix.GetCreatorGoalsParams{
+		BroadcasterID: broadcasterUserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(goalsResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+
+	update := GoalsUpdate{}
+	for _, goal := range goalsResp.Data.Goals {
+		update.Goals = append(update.Goals, Goal{
+			ID:            goal.ID,
+			Type:          goal.Type,
+			Description:   goal.Description,
+			CurrentAmount: goal.CurrentAmount,
+			TargetAmount:  goal.TargetAmount,
+			UnixCreatedAt: unixTime(goal.CreatedAt.Time),
+		})
+	}
+
+	return update, nil
+}
+
+// fetchHypeTrain returns the newest hype train, it is active if it did not expire yet.
+func fetchHypeTrain(client *helix.Client, broadcasterUserID string) (interface{}, error) {
+	hypeTrainResp, err := client.GetHypeTrainEvents(&helix.HypeTrainEventsParams{
+		BroadcasterID: broadcasterUserID,
+		First:         1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := helixError(hypeTrainResp.ResponseCommon); err != nil {
+		return nil, err
+	}
+	if len(hypeTrainResp.Data.Events) <= 0 {
+		return nil, nil
+	}
+
+	train := hypeTrainResp.Data.Events[0].Event
+	return HypeTrainUpdate{HypeTrain{
+		ID:                train.ID,
+		IsActive:          train.ExpiresAt.After(time.Now()),
+		Level:             int(train.Level),
+		Total:             int(train.Total),
+		Goal:              int(train.Goal),
+		UnixStartedAt:     unixTime(train.StartedAt.Time),
+		UnixExpiresAt:     unixTime(train.ExpiresAt.Time),
+		UnixCooldownEndAt: unixTime(train.CooldownEndTime.Time),
+	}}, nil
+}
+
+func pollFromHelix(poll helix.Poll) Poll {
+	var choices []Choice
+	for _, choice := range poll.Choices {
+		choices = append(choices, Choice{
+			ID:                 choice.ID,
+			Title:              choice.Title,
+			BitsVoted:          choice.BitsVotes,
+			ChannelPointsVoted: choice.ChannelPointsVotes,
+			Votes:              choice.Votes,
+		})
+	}
+
+	return Poll{
+		ID:            poll.ID,
+		IsActive:      poll.Status == "ACTIVE",
+		Title:         poll.Title,
+		Status:        strings.ToLower(poll.Status),
+		Choices:       choices,
+		UnixStartedAt: unixTime(poll.StartedAt.Time),
+		UnixEndsAt:    unixTime(poll.StartedAt.Add(time.Duration(poll.Duration) * time.Second)),
+	}
+}
+
+// pollFromEvent sets the status to active for poll.begin and poll.progress, which have none.
+func pollFromEvent(event lib.ChannelPollEventV1, choices []Choice, isActive bool) Poll {
+	status := event.Status
+	if status == "" && isActive {
+		status = "active"
+	}
+
+	return Poll{
+		ID:            event.ID,
+		IsActive:      isActive,
+		Title:         event.Title,
+		Status:        status,
+		Choices:       choices,
+		UnixStartedAt: unixTime(event.StartedAt),
+		UnixEndsAt:    unixTime(event.EndsAt),
+	}
+}
+
+func predictionFromHelix(prediction helix.Prediction) Prediction {
+	var outcomes []PredictionOutcome
+	for _, outcome := range prediction.Outcomes {
+		var topPredictors []TopPredictor
+		for _, topPredictor := range outcome.TopPredictors {
+			topPredictors = append(topPredictors, TopPredictor{
+				UserID:            topPredictor.UserID,
+				UserName:          topPredictor.UserName,
+				ChannelPointsUsed: topPredictor.ChannelPointsUsed,
+				ChannelPointsWon:  topPredictor.ChannelPointsWon,
+			})
+		}
+
+		outcomes = append(outcomes, PredictionOutcome{
+			ID:            outcome.ID,
+			Title:         outcome.Title,
+			Color:         strings.ToLower(outcome.Color),
+			Users:         outcome.Users,
+			ChannelPoints: outcome.ChannelPoints,
+			TopPredictors: topPredictors,
+		})
+	}
+
+	locksAt := prediction.CreatedAt.Add(time.Duration(prediction.PredictionWindow) * time.Second)
+	return Prediction{
+		ID:            prediction.ID,
+		IsActive:      prediction.Status == "ACTIVE" || prediction.Status == "LOCKED",
+		Title:         prediction.Title,
+		Status:        strings.ToLower(prediction.Status),
+		Outcomes:      outcomes,
+		UnixStartedAt: unixTime(prediction.CreatedAt.Time),
+		UnixLocksAt:   unixTime(locksAt),
+	}
+}
+
+func predictionFromEvent(event lib.ChannelPredictionEventV1, outcomes []PredictionOutcome, status string) Prediction {
+	return Prediction{
+		ID:            event.ID,
+		IsActive:      status == "active" || status == "locked",
+		Title:         event.Title,
+		Status:        status,
+		Outcomes:      outcomes,
+		UnixStartedAt: unixTime(event.StartedAt),
+		UnixLocksAt:   unixTime(event.LocksAt),
+	}
+}
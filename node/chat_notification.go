@@ -0,0 +1,272 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+
+	"github.com/nicklaw5/helix/v2"
+	"graphics.gd/variant/Float"
+)
+
+type ChatNotification struct {
+	Type               string            `gd:"type"`
+	ChatterUserID      string            `gd:"chatter_user_id"`
+	ChatterUserLogin   string            `gd:"chatter_user_login"`
+	ChatterUserName    string            `gd:"chatter_user_name"`
+	ChatterIsAnonymous bool              `gd:"chatter_is_anonymous"`
+	Color              string            `gd:"color"`
+	SystemMessage      string            `gd:"system_message"`
+	MessageID          string            `gd:"message_id"`
+	MessageText        string            `gd:"message_text"`
+	MessageFragments   []MessageFragment `gd:"message_fragments"`
+
+	Sub              SubNotice              `gd:"sub"`
+	Resub            ResubNotice            `gd:"resub"`
+	SubGift          SubGiftNotice          `gd:"sub_gift"`
+	CommunitySubGift CommunitySubGiftNotice `gd:"community_sub_gift"`
+	GiftPaidUpgrade  GifterNotice           `gd:"gift_paid_upgrade"`
+	PrimePaidUpgrade PrimePaidUpgradeNotice `gd:"prime_paid_upgrade"`
+	Raid             RaidNotice             `gd:"raid"`
+	PayItForward     GifterNotice           `gd:"pay_it_forward"`
+	Announcement     AnnouncementNotice     `gd:"announcement"`
+	BitsBadgeTier    BitsBadgeTierNotice    `gd:"bits_badge_tier"`
+	CharityDonation  CharityDonationNotice  `gd:"charity_donation"`
+}
+
+type MessageFragment struct {
+	Type             string `gd:"type"`
+	Text             string `gd:"text"`
+	EmoteID          string `gd:"emote_id"`
+	EmoteSetID       string `gd:"emote_set_id"`
+	CheermotePrefix  string `gd:"cheermote_prefix"`
+	CheermoteBits    int    `gd:"cheermote_bits"`
+	CheermoteTier    int    `gd:"cheermote_tier"`
+	MentionUserID    string `gd:"mention_user_id"`
+	MentionUserLogin string `gd:"mention_user_login"`
+	MentionUserName  string `gd:"mention_user_name"`
+}
+
+type SubNotice struct {
+	Tier           int  `gd:"tier"`
+	IsPrime        bool `gd:"is_prime"`
+	DurationMonths int  `gd:"duration_months"`
+}
+
+type ResubNotice struct {
+	Tier              int    `gd:"tier"`
+	IsPrime           bool   `gd:"is_prime"`
+	CumulativeMonths  int    `gd:"cumulative_months"`
+	DurationMonths    int    `gd:"duration_months"`
+	StreakMonths      int    `gd:"streak_months"`
+	IsGift            bool   `gd:"is_gift"`
+	GifterIsAnonymous bool   `gd:"gifter_is_anonymous"`
+	GifterUserID      string `gd:"gifter_user_id"`
+	GifterUserLogin   string `gd:"gifter_user_login"`
+	GifterUserName    string `gd:"gifter_user_name"`
+}
+
+type SubGiftNotice struct {
+	Tier               int    `gd:"tier"`
+	DurationMonths     int    `gd:"duration_months"`
+	CumulativeTotal    int    `gd:"cumulative_total"`
+	RecipientUserID    string `gd:"recipient_user_id"`
+	RecipientUserLogin string `gd:"recipient_user_login"`
+	RecipientUserName  string `gd:"recipient_user_name"`
+	CommunityGiftID    string `gd:"community_gift_id"`
+}
+
+type CommunitySubGiftNotice struct {
+	ID              string `gd:"id"`
+	Tier            int    `gd:"tier"`
+	Total           int    `gd:"total"`
+	CumulativeTotal int    `gd:"cumulative_total"`
+}
+
+// GifterNotice is used for gift_paid_upgrade and pay_it_forward notices.
+type GifterNotice struct {
+	GifterIsAnonymous bool   `gd:"gifter_is_anonymous"`
+	GifterUserID      string `gd:"gifter_user_id"`
+	GifterUserLogin   string `gd:"gifter_user_login"`
+	GifterUserName    string `gd:"gifter_user_name"`
+}
+
+type PrimePaidUpgradeNotice struct {
+	Tier int `gd:"tier"`
+}
+
+type RaidNotice struct {
+	UserID            string `gd:"user_id"`
+	UserLogin         string `gd:"user_login"`
+	UserName          string `gd:"user_name"`
+	ViewerCount       int    `gd:"viewer_count"`
+	ProfilePictureURL string `gd:"profile_picture_url"`
+}
+
+type AnnouncementNotice struct {
+	Color string `gd:"color"`
+}
+
+type BitsBadgeTierNotice struct {
+	Tier int `gd:"tier"`
+}
+
+type CharityDonationNotice struct {
+	CharityName   string  `gd:"charity_name"`
+	Value         int     `gd:"value"`
+	DecimalPlaces int     `gd:"decimal_places"`
+	Currency      string  `gd:"currency"`
+	Amount        Float.X `gd:"amount"`
+}
+
+func chatNotificationFromEvent(event lib.ChatNotificationEventV1) (ChatNotification, error) {
+	var notification ChatNotification
+	notification.Type = event.NoticeType
+	notification.ChatterUserID = event.ChatterUserID
+	notification.ChatterUserLogin = event.ChatterUserLogin
+	notification.ChatterUserName = event.ChatterUserName
+	notification.ChatterIsAnonymous = event.ChatterIsAnonymous
+	notification.Color = event.Color
+	notification.SystemMessage = event.SystemMessage
+	notification.MessageID = event.MessageID
+	notification.MessageText = event.Message.Text
+	notification.MessageFragments = messageFragmentsFromEvent(event.Message.Fragments)
+
+	var err error
+
+	// only the object matching notice_type is set, all others are null
+	switch helix.EventSubChannelChatNotificationType(event.NoticeType) {
+	case helix.EventSubChannelNotificationSub:
+		if event.Sub == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.Sub.Tier, err = parseTier(event.Sub.SubTier)
+		notification.Sub.IsPrime = event.Sub.IsPrime
+		notification.Sub.DurationMonths = event.Sub.DurationMonths
+	case helix.EventSubChannelNotificationResub:
+		if event.Resub == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.Resub.Tier, err = parseTier(event.Resub.SubTier)
+		notification.Resub.IsPrime = event.Resub.IsPrime
+		notification.Resub.CumulativeMonths = event.Resub.CumulativeMonths
+		notification.Resub.DurationMonths = event.Resub.DurationMonths
+		notification.Resub.StreakMonths = event.Resub.StreakMonths
+		notification.Resub.IsGift = event.Resub.IsGift
+		notification.Resub.GifterIsAnonymous = event.Resub.GifterIsAnonymous
+		notification.Resub.GifterUserID = event.Resub.GifterUserID
+		notification.Resub.GifterUserLogin = event.Resub.GifterUserLogin
+		notification.Resub.GifterUserName = event.Resub.GifterUserName
+	case helix.EventSubChannelNotificationSubGift:
+		if event.SubGift == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.SubGift.Tier, err = parseTier(event.SubGift.SubTier)
+		notification.SubGift.DurationMonths = event.SubGift.DurationMonths
+		notification.SubGift.CumulativeTotal = event.SubGift.CumulativeTotal
+		notification.SubGift.RecipientUserID = event.SubGift.RecipientUserID
+		notification.SubGift.RecipientUserLogin = event.SubGift.RecipientUserLogin
+		notification.SubGift.RecipientUserName = event.SubGift.RecipientUserName
+		notification.SubGift.CommunityGiftID = event.SubGift.CommunityGiftID
+	case helix.EventSubChannelNotificationCommunitySubGift:
+		if event.CommunitySubGift == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.CommunitySubGift.ID = event.CommunitySubGift.ID
+		notification.CommunitySubGift.Tier, err = parseTier(event.CommunitySubGift.SubTier)
+		notification.CommunitySubGift.Total = event.CommunitySubGift.Total
+		notification.CommunitySubGift.CumulativeTotal = event.CommunitySubGift.CumulativeTotal
+	case helix.EventSubChannelNotificationGiftPaidUpgrade:
+		if event.GiftPaidUpgrade == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.GiftPaidUpgrade = gifterNoticeFromEvent(*event.GiftPaidUpgrade)
+	case helix.EventSubChannelNotificationPrimePaidUpgrade:
+		if event.PrimePaidUpgrade == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.PrimePaidUpgrade.Tier, err = parseTier(event.PrimePaidUpgrade.SubTier)
+	case helix.EventSubChannelNotificationRaid:
+		if event.Raid == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.Raid.UserID = event.Raid.UserID
+		notification.Raid.UserLogin = event.Raid.UserLogin
+		notification.Raid.UserName = event.Raid.UserName
+		notification.Raid.ViewerCount = event.Raid.ViewerCount
+		notification.Raid.ProfilePictureURL = event.Raid.ProfileImageURL
+	case helix.EventSubChannelNotificationPayItForward:
+		if event.PayItForward == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.PayItForward = gifterNoticeFromEvent(*event.PayItForward)
+	case helix.EventSubChannelNotificationAnnouncement:
+		if event.Announcement == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.Announcement.Color = event.Announcement.Color
+	case helix.EventSubChannelNotificationBitsBadgeTier:
+		if event.BitsBadgeTier == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		notification.BitsBadgeTier.Tier = event.BitsBadgeTier.Tier
+	case helix.EventSubChannelNotificationCharityDonation:
+		if event.CharityDonation == nil {
+			return notification, errMissingNotice(event.NoticeType)
+		}
+		amount := event.CharityDonation.Amount
+		notification.CharityDonation.CharityName = event.CharityDonation.CharityName
+		notification.CharityDonation.Value = amount.Value
+		notification.CharityDonation.DecimalPlaces = amount.DecimalPlaces
+		notification.CharityDonation.Currency = amount.Currency
+		notification.CharityDonation.Amount = minorUnitsToFloat(amount.Value, amount.DecimalPlaces)
+	case helix.EventSubChannelNotificationUnraid:
+	default:
+		lib.LogWarn(fmt.Sprintf("unknown chat notification type %s", event.NoticeType))
+	}
+
+	return notification, err
+}
+
+func errMissingNotice(noticeType string) error {
+	return fmt.Errorf("missing notice data for chat notification type %s", noticeType)
+}
+
+func gifterNoticeFromEvent(notice lib.GifterNotice) GifterNotice {
+	var gifter GifterNotice
+	gifter.GifterIsAnonymous = notice.GifterIsAnonymous
+	if !gifter.GifterIsAnonymous {
+		gifter.GifterUserID = notice.GifterUserID
+		gifter.GifterUserLogin = notice.GifterUserLogin
+		gifter.GifterUserName = notice.GifterUserName
+	}
+
+	return gifter
+}
+
+func messageFragmentsFromEvent(fragments []lib.MessageFragment) []MessageFragment {
+	var fragmentsArray []MessageFragment
+	for _, fragment := range fragments {
+		var fragmentDict MessageFragment
+		fragmentDict.Type = fragment.Type
+		fragmentDict.Text = fragment.Text
+
+		if fragment.Emote != nil {
+			fragmentDict.EmoteID = fragment.Emote.ID
+			fragmentDict.EmoteSetID = fragment.Emote.EmoteSetID
+		}
+		if fragment.Cheermote != nil {
+			fragmentDict.CheermotePrefix = fragment.Cheermote.Prefix
+			fragmentDict.CheermoteBits = fragment.Cheermote.Bits
+			fragmentDict.CheermoteTier = fragment.Cheermote.Tier
+		}
+		if fragment.Mention != nil {
+			fragmentDict.MentionUserID = fragment.Mention.UserID
+			fragmentDict.MentionUserLogin = fragment.Mention.UserLogin
+			fragmentDict.MentionUserName = fragment.Mention.UserName
+		}
+
+		fragmentsArray = append(fragmentsArray, fragmentDict)
+	}
+
+	return fragmentsArray
+}
@@ -0,0 +1,182 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"main/lib"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// enrichmentTimeout limits how long an event waits for helix lookups before it gets emitted with
+// fallback values.
+const enrichmentTimeout = 3 * time.Second
+
+// enrichmentMaxLookups limits the lookups running at once, timed out lookups count until their
+// request finishes.
+const enrichmentMaxLookups = 8
+
+// user and channel lookups are cached, results of timed out lookups still end up in the cache
+const (
+	userCacheTTL  = 10 * time.Minute
+	userCacheSize = 1000
+)
+
+var errEnrichmentTimeout = errors.New("timed out")
+
+// eventEnrichment holds helix data some signals need but the event payload does not include.
+// Fields stay empty if the lookup failed.
+type eventEnrichment struct {
+	profilePictureURL string
+	lastStreamGame    string
+	lastStreamTitle   string
+}
+
+// enrichedMessage is pending while its helix lookups run, it is guarded by eventProcessLock.
+type enrichedMessage struct {
+	msg        lib.TwitchMessage
+	enrichment eventEnrichment
+	simulated  bool
+	pending    bool
+}
+
+// enrichAndQueue queues the message for emission on the main thread in the order of arrival. Events
+// that need helix data are enriched in the background, they and the events received after them are
+// held back until the lookups finished or timed out. Replayed events are marked as simulated.
+func (h *GodotTwitch) enrichAndQueue(msg lib.TwitchMessage, simulated bool) {
+	if msg.Payload.Subscription == nil {
+		h.queueEvent(&enrichedMessage{msg: msg, simulated: simulated})
+		return
+	}
+
+	subscription := msg.Payload.Subscription
+	decodedEvent, err := lib.DecodeEvent(subscription.Type, subscription.Version, msg.Payload.Event)
+	if err != nil {
+		// decode errors are reported when the event is handled
+		h.queueEvent(&enrichedMessage{msg: msg, simulated: simulated})
+		return
+	}
+
+	var userID string
+	var withChannelInfo bool
+	switch event := decodedEvent.(type) {
+	case lib.ChannelRaidEventV1:
+		if event.FromBroadcasterUserID != h.currentBroadcasterUserID() {
+			userID = event.FromBroadcasterUserID
+		}
+	case lib.ShoutoutCreateEventV1:
+		userID = event.ToBroadcasterUserID
+		withChannelInfo = true
+	case lib.ShoutoutReceiveEventV1:
+		userID = event.FromBroadcasterUserID
+	}
+
+	// without credentials, e.g. while playing back a recording, there is nothing to look up
+	if userID == "" || h.userCache == nil {
+		h.queueEvent(&enrichedMessage{msg: msg, simulated: simulated})
+		return
+	}
+
+	message := &enrichedMessage{msg: msg, simulated: simulated, pending: true}
+	h.queueEvent(message)
+
+	userCache := h.userCache
+	lookups := h.enrichmentLookups
+	go func() {
+		var enrichment eventEnrichment
+
+		profilePictureURL, err := fetchProfilePicture(userCache, lookups, userID)
+		if err != nil {
+			lib.LogWarn(fmt.Sprintf("unable to fetch profile picture for %s: %s", subscription.Type, err.Error()))
+		}
+		enrichment.profilePictureURL = profilePictureURL
+
+		if withChannelInfo {
+			channel, err := fetchChannelInformation(userCache, lookups, userID)
+			if err != nil {
+				lib.LogWarn(fmt.Sprintf("unable to fetch channel info for %s: %s", subscription.Type, err.Error()))
+			}
+			enrichment.lastStreamGame = channel.GameName
+			enrichment.lastStreamTitle = channel.Title
+		}
+
+		h.eventProcessLock.Lock()
+		message.enrichment = enrichment
+		message.pending = false
+		h.eventProcessLock.Unlock()
+	}()
+}
+
+func (h *GodotTwitch) queueEvent(msg *enrichedMessage) {
+	h.eventProcessLock.Lock()
+	h.eventProcessQueue = append(h.eventProcessQueue, msg)
+	h.eventProcessLock.Unlock()
+}
+
+func fetchProfilePicture(userCache *lib.UserCache, lookups chan struct{}, userID string) (string, error) {
+	user, err := withTimeout(lookups, func() (helix.User, error) {
+		users, err := userCache.GetUsersByID([]string{userID})
+		if err != nil {
+			return helix.User{}, fmt.Errorf("unable to fetch user %s: %w", userID, err)
+		}
+		user, ok := users[userID]
+		if !ok {
+			return helix.User{}, fmt.Errorf("unable to fetch user %s: empty result", userID)
+		}
+
+		return user, nil
+	})
+
+	return user.ProfileImageURL, err
+}
+
+func fetchChannelInformation(userCache *lib.UserCache, lookups chan struct{}, broadcasterID string) (helix.ChannelInformation, error) {
+	return withTimeout(lookups, func() (helix.ChannelInformation, error) {
+		channels, err := userCache.GetChannels([]string{broadcasterID})
+		if err != nil {
+			return helix.ChannelInformation{}, fmt.Errorf("unable to fetch channel info for %s: %w", broadcasterID, err)
+		}
+		channel, ok := channels[broadcasterID]
+		if !ok {
+			return helix.ChannelInformation{}, fmt.Errorf("unable to fetch channel %s: empty result", broadcasterID)
+		}
+
+		return channel, nil
+	})
+}
+
+// withTimeout stops waiting for fetch after enrichmentTimeout, waiting for a free slot in lookups
+// counts towards it. The helix client has no per request context and lookups are shared by the
+// user cache, so a timed out request keeps running in the background and holds its slot until it
+// finishes. Its result is dropped.
+func withTimeout[T any](lookups chan struct{}, fetch func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	var zero T
+	timeout := time.After(enrichmentTimeout)
+
+	select {
+	case lookups <- struct{}{}:
+	case <-timeout:
+		return zero, errEnrichmentTimeout
+	}
+
+	resultChan := make(chan result, 1)
+	go func() {
+		defer func() { <-lookups }()
+
+		value, err := fetch()
+		resultChan <- result{value, err}
+	}()
+
+	select {
+	case res := <-resultChan:
+		return res.value, res.err
+	case <-timeout:
+		return zero, errEnrichmentTimeout
+	}
+}
@@ -1,280 +1,581 @@
 package node
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"main/lib"
 	"time"
 
 	"github.com/nicklaw5/helix/v2"
-	"graphics.gd/variant/Float"
 )
 
+// handleEventTick takes the queued events up to the first one still waiting for enrichment before
+// handling them, so signal handlers can queue new events and goroutines do not wait for signal
+// handlers.
 func (h *GodotTwitch) handleEventTick() {
 	h.eventProcessLock.Lock()
-	defer h.eventProcessLock.Unlock()
-
-	if len(h.eventProcessQueue) <= 0 {
-		return
+	ready := len(h.eventProcessQueue)
+	for i, msg := range h.eventProcessQueue {
+		if msg.pending {
+			ready = i
+			break
+		}
 	}
+	queue := h.eventProcessQueue[:ready]
+	h.eventProcessQueue = append([]*enrichedMessage(nil), h.eventProcessQueue[ready:]...)
+	h.eventProcessLock.Unlock()
 
-	for _, msg := range h.eventProcessQueue {
-		h.handleEvent(msg)
+	for _, msg := range queue {
+		h.handleEvent(msg.msg, msg.enrichment, msg.simulated)
 	}
-	h.eventProcessQueue = make([]lib.TwitchMessage, 0)
 }
 
-func (h *GodotTwitch) handleEvent(eventMsg lib.TwitchMessage) {
+// simulated and replayed events are handled like real ones but not written to the journal. Real
+// notifications that are already journaled are redeliveries and dropped.
+func (h *GodotTwitch) handleEvent(eventMsg lib.TwitchMessage, enrichment eventEnrichment, simulated bool) {
 	if eventMsg.Payload.Subscription == nil {
 		fmt.Printf("%+v\n", eventMsg)
 		lib.LogWarn(fmt.Sprintf("received non subscribtion event: %s", eventMsg.Metadata.Type))
 		return
 	}
 
-	lib.LogInfo(fmt.Sprintf("received event for: %s", eventMsg.Payload.Subscription.Type))
+	subType := eventMsg.Payload.Subscription.Type
+	subVersion := eventMsg.Payload.Subscription.Version
+	lib.LogInfo(fmt.Sprintf("received event for: %s", subType))
 
-	switch eventMsg.Payload.Subscription.Type {
-	case helix.EventSubTypeChannelFollow:
-		tmpName := h.readStringFromEvent(eventMsg.Payload.Event, "user_name")
-
-		h.LatestFollower = tmpName
+	var rawEvent map[string]interface{}
+	if err := json.Unmarshal(eventMsg.Payload.Event, &rawEvent); err != nil {
+		h.OnEventError.Emit(subType, fmt.Sprintf("unable to read event: %s", err.Error()))
+		return
+	}
 
-		h.OnFollow.Emit(tmpName)
-	case helix.EventSubTypeChannelSubscription:
-		tmpName := h.readStringFromEvent(eventMsg.Payload.Event, "user_name")
+	if !simulated && !h.journalEvent(eventMsg) {
+		return
+	}
 
-		h.LatestSubscriber = tmpName
+	godotEvent := toGodotValue(rawEvent).(map[string]interface{})
+	h.OnEvent.Emit(
+		subType,
+		subVersion,
+		godotEvent,
+		map[string]interface{}{
+			"message_id":           eventMsg.Metadata.ID,
+			"message_type":         eventMsg.Metadata.Type,
+			"message_timestamp":    eventMsg.Metadata.Timestamp,
+			"subscription_id":      eventMsg.Payload.Subscription.ID,
+			"subscription_type":    subType,
+			"subscription_version": subVersion,
+		},
+	)
+
+	h.evaluateRules(subType, godotEvent)
+
+	decodedEvent, err := lib.DecodeEvent(subType, subVersion, eventMsg.Payload.Event)
+	if errors.Is(err, lib.ErrUnknownEventType) {
+		// only available through on_event
+		return
+	}
+	if err != nil {
+		lib.LogErr(err.Error())
+		h.OnEventError.Emit(subType, err.Error())
+		return
+	}
 
-		// only emit signal for non gift subs as we emit from the gift event for gifts and we
-		// we do not want double events
-		if !eventMsg.Payload.Event["is_gift"].(bool) {
-			tier := h.readIntFromEvent(eventMsg.Payload.Event, "tier")
-			h.OnSubscibtion.Emit(tmpName, 1, tier)
-		}
-	case helix.EventSubTypeChannelSubscriptionMessage:
-		tmpName := h.readStringFromEvent(eventMsg.Payload.Event, "user_name")
+	if !simulated {
+		h.recordSessionStats(subType, decodedEvent)
+	}
 
-		h.LatestSubscriber = tmpName
+	if err := h.emitEvent(eventMsg, decodedEvent, enrichment); err != nil {
+		lib.LogErr(fmt.Sprintf("unable to handle %s: %s", subType, err.Error()))
+		h.OnEventError.Emit(subType, err.Error())
+	}
+}
 
-		tier := h.readIntFromEvent(eventMsg.Payload.Event, "tier")
-		if tier >= 1000 {
-			tier = tier % 1000
-		}
-		months := h.readIntFromEvent(eventMsg.Payload.Event, "cumulative_months")
-		h.OnSubscibtion.Emit(tmpName, months, tier)
-	case helix.EventSubTypeChannelSubscriptionGift:
-		isAnonymous := h.readBoolFromEvent(eventMsg.Payload.Event, "is_anonymous")
-		var gifterName string
-		var totalAmountForUser int
-		if !isAnonymous {
-			gifterName = h.readStringFromEvent(eventMsg.Payload.Event, "user_name")
-			totalAmountForUser = h.readIntFromEvent(eventMsg.Payload.Event, "cumulative_total")
-		} else {
-			gifterName = ""
-		}
-		tier := h.readIntFromEvent(eventMsg.Payload.Event, "tier")
-		giftAmount := h.readIntFromEvent(eventMsg.Payload.Event, "total")
-
-		h.OnGiftSubs.Emit(gifterName, giftAmount, tier, totalAmountForUser)
-	case helix.EventSubTypeChannelRaid:
-		fromUserID := h.readStringFromEvent(eventMsg.Payload.Event, "from_broadcaster_user_id")
-		fromUser := h.readStringFromEvent(eventMsg.Payload.Event, "from_broadcaster_user_name")
-		viewerCount := h.readIntFromEvent(eventMsg.Payload.Event, "viewers")
-
-		userResp, err := h.twitchClient.GetUsers(&helix.UsersParams{
-			IDs: []string{fromUserID},
-		})
+func (h *GodotTwitch) emitEvent(eventMsg lib.TwitchMessage, decodedEvent interface{}, enrichment eventEnrichment) error {
+	subType := eventMsg.Payload.Subscription.Type
+	meta := eventMetaFrom(eventMsg)
+
+	switch event := decodedEvent.(type) {
+	case lib.ChannelFollowEventV2:
+		h.LatestFollower = event.UserName
+		h.latestFollowerFromEvent = true
+		h.FollowerTotal++
+
+		h.OnFollow.Emit(event.UserName)
+		follow := newFollowEvent(meta, event)
+		h.OnFollowEvent.Emit(follow)
+		h.pushAlert(AlertTypeFollow, follow)
+	case lib.ChannelSubscribeEventV1:
+		h.LatestSubscriber = event.UserName
+		h.latestSubscriberFromEvent = true
+
+		tier, err := parseTier(event.Tier)
 		if err != nil {
-			lib.LogErr(fmt.Sprintf("unable to fetch user %s: %s", fromUserID, err.Error()))
-			return
-		}
-		if len(userResp.Data.Users) <= 0 {
-			lib.LogErr(fmt.Sprintf("unable to fetch user %s: empty result", fromUserID))
-			return
+			return err
 		}
 
-		userObj := userResp.Data.Users[0]
-		profilePicUrl := userObj.ProfileImageURL
-
-		h.OnIncomingRaid.Emit(fromUser, profilePicUrl, viewerCount)
-	case helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd:
-		rewardInterface, ok := eventMsg.Payload.Event["reward"]
-		if !ok {
-			lib.LogErr("missing event data: reward")
-			return
+		// only emit signal for non gift subs as we emit from the gift event for gifts and we
+		// we do not want double events
+		if event.IsGift {
+			h.addGiftedSub(lib.GiftedSub{
+				Tier:        tier,
+				RecipientID: event.UserID,
+				Recipient:   event.UserName,
+				ReceivedAt:  time.Now(),
+			})
+			return nil
 		}
 
-		reward := rewardInterface.(map[string]interface{})
-		fromUser := h.readStringFromEvent(eventMsg.Payload.Event, "user_name")
-		userInput := h.readStringFromEvent(eventMsg.Payload.Event, "user_input")
-		rewardID := h.readStringFromEvent(reward, "id")
-		rewardTitle := h.readStringFromEvent(reward, "title")
-		rewardPrompt := h.readStringFromEvent(reward, "prompt")
-		rewardCost := h.readIntFromEvent(reward, "cost")
+		h.OnSubscibtion.Emit(event.UserName, 1, tier)
 
-		h.OnRewardRedemtionAdd.Emit(
-			fromUser, userInput,
-			rewardID, rewardTitle, rewardPrompt, rewardCost,
-		)
-	case helix.EventSubShoutoutCreate:
-		broadcasterID := h.readStringFromEvent(eventMsg.Payload.Event, "to_broadcaster_user_id")
-		broadcasterName := h.readStringFromEvent(eventMsg.Payload.Event, "to_broadcaster_user_name")
+		subscription := newSubscriptionEvent(meta, event.UserRef, tier)
+		subscription.CumulativeMonths = 1
+		h.OnSubscriptionEvent.Emit(subscription)
+		h.pushAlert(AlertTypeSubscription, subscription)
+	case lib.ChannelSubscriptionMessageEventV1:
+		h.LatestSubscriber = event.UserName
+		h.latestSubscriberFromEvent = true
 
-		userResp, err := h.twitchClient.GetUsers(&helix.UsersParams{
-			IDs: []string{broadcasterID},
-		})
+		tier, err := parseTier(event.Tier)
 		if err != nil {
-			lib.LogErr(fmt.Sprintf("unable to fetch user %s: %s", broadcasterID, err.Error()))
-			return
+			return err
 		}
-		if len(userResp.Data.Users) <= 0 {
-			lib.LogErr(fmt.Sprintf("unable to fetch user %s: empty result", broadcasterID))
-			return
+		subscription := newSubscriptionEvent(meta, event.UserRef, tier)
+		subscription.IsResub = true
+		subscription.CumulativeMonths = event.CumulativeMonths
+		subscription.StreakMonths = event.StreakMonths
+		subscription.DurationMonths = event.DurationMonths
+		subscription.MessageText = event.Message.Text
+		subscription.MessageFragments = messageFragmentsFromEvent(lib.MessageFragmentsFromEmotes(event.Message.Text, event.Message.Emotes))
+		for _, emote := range event.Message.Emotes {
+			h.preloadEmote(emote.ID)
 		}
 
-		userObj := userResp.Data.Users[0]
-		profilePicUrl := userObj.ProfileImageURL
-
-		channelInfo, err := h.twitchClient.GetChannelInformation(&helix.GetChannelInformationParams{
-			BroadcasterIDs: []string{broadcasterID},
-		})
-		if err != nil {
-			lib.LogErr(fmt.Sprintf("unable to fetch channel info for %s: %s", broadcasterID, err.Error()))
-			return
+		if tier >= 1000 {
+			tier = tier % 1000
 		}
-		if len(channelInfo.Data.Channels) <= 0 {
-			lib.LogErr(fmt.Sprintf("unable to fetch channel %s: empty result", broadcasterID))
-			return
+		h.OnSubscibtion.Emit(event.UserName, event.CumulativeMonths, tier)
+		h.OnSubscriptionEvent.Emit(subscription)
+		h.pushAlert(AlertTypeSubscription, subscription)
+	case lib.ChannelSubscriptionGiftEventV1:
+		tier, err := parseTier(event.Tier)
+		if err != nil {
+			return err
 		}
 
-		channelObj := channelInfo.Data.Channels[0]
-		lastGameName := channelObj.GameName
-		lastStreamTitle := channelObj.Title
+		// anonymous gifts come without user and cumulative total
+		var gifterID string
+		var gifterName string
+		var totalAmountForUser int
+		if !event.IsAnonymous {
+			gifterID = event.UserID
+			gifterName = event.UserName
+			totalAmountForUser = event.CumulativeTotal
+		}
 
-		h.OnShoutoutCreate.Emit(broadcasterName, profilePicUrl, lastGameName, lastStreamTitle)
-	case helix.EventSubTypeCharityDonation:
-		amountInterface, ok := eventMsg.Payload.Event["amount"]
-		if !ok {
-			lib.LogErr("missing event data: amount")
-			return
+		h.OnGiftSubs.Emit(gifterName, event.Total, tier, totalAmountForUser)
+		giftSub := newGiftSubEvent(meta, event, tier)
+		h.OnGiftSubEvent.Emit(giftSub)
+		// the gift alert is pushed once the recipients are known
+		h.startGiftBomb(gifterID, gifterName, tier, event.Total, giftSub)
+	case lib.ChannelCheerEventV1:
+		var username string
+		if !event.IsAnonymous {
+			username = event.UserName
+		}
+		h.OnCheer.Emit(username, event.Bits, event.Message)
+		cheer := newCheerEvent(meta, event)
+		h.OnCheerEvent.Emit(cheer)
+		h.pushAlert(AlertTypeCheer, cheer)
+		h.refreshBitsLeaderboard()
+	case lib.ChannelRaidEventV1:
+		if event.FromBroadcasterUserID == h.currentBroadcasterUserID() {
+			h.OnOutgoingRaid.Emit(event.ToBroadcasterUserName, event.Viewers)
+			h.OnRaidEvent.Emit(newRaidEvent(meta, event, true))
+			return nil
 		}
 
-		amount := amountInterface.(map[string]interface{})
+		profilePicUrl := enrichment.profilePictureURL
 
-		donatorName := h.readStringFromEvent(eventMsg.Payload.Event, "user_name")
-		value := h.readIntFromEvent(amount, "value")
-		decimalPlaces := h.readIntFromEvent(amount, "decimal_places")
-		currency := h.readStringFromEvent(amount, "currency")
+		h.OnIncomingRaid.Emit(event.FromBroadcasterUserName, profilePicUrl, event.Viewers)
 
-		valueFloat := Float.X(value)
-		if decimalPlaces > 0 {
-			valueFloat = Float.X(value) / Float.Pow(10, Float.X(decimalPlaces))
-		}
+		raid := newRaidEvent(meta, event, false)
+		raid.ProfilePictureURL = profilePicUrl
+		h.OnRaidEvent.Emit(raid)
+		h.pushAlert(AlertTypeRaid, raid)
+	case lib.ChannelPointsRedemptionEventV1:
+		redemption := redemptionFromEvent(event)
+		redemptionEvent := newRedemptionEvent(meta, redemption)
+		h.OnRedemptionEvent.Emit(redemptionEvent)
 
-		h.OnDonation.Emit(donatorName, valueFloat, currency)
-	case helix.EventSubTypeChannelPollBegin:
-		title := h.readStringFromEvent(eventMsg.Payload.Event, "title")
-		endsAtStr := h.readStringFromEvent(eventMsg.Payload.Event, "ends_at")
-		endsAt, err := time.Parse(time.RFC3339, endsAtStr)
-		if err != nil {
-			lib.LogErr(fmt.Sprintf("error converting timestamp: %s", err.Error()))
-			return
+		if subType == helix.EventSubTypeChannelPointsCustomRewardRedemptionUpdate {
+			h.OnRedemptionUpdate.Emit(redemption)
+			return nil
 		}
 
-		choicesArray := h.readPollChoices(eventMsg, true)
-		if choicesArray == nil {
-			return
-		}
-
-		h.OnPollBegin.Emit(title, int(endsAt.Unix()), choicesArray)
-	case helix.EventSubTypeChannelPollProgress:
-		title := h.readStringFromEvent(eventMsg.Payload.Event, "title")
+		h.pushAlert(AlertTypeRedemption, redemptionEvent)
 
-		choicesArray := h.readPollChoices(eventMsg, false)
-		if choicesArray == nil {
-			return
+		h.OnRewardRedemtionAdd.Emit(
+			event.UserName, event.UserInput,
+			event.Reward.ID, event.Reward.Title, event.Reward.Prompt, event.Reward.Cost,
+		)
+		h.OnRedemption.Emit(redemption)
+	case lib.ChannelPointsRewardEventV1:
+		reward := rewardFromEvent(event)
+
+		switch subType {
+		case helix.EventSubTypeChannelPointsCustomRewardAdd:
+			h.rewardCatalog[reward.ID] = reward
+			h.OnRewardAdd.Emit(reward)
+		case helix.EventSubTypeChannelPointsCustomRewardUpdate:
+			h.rewardCatalog[reward.ID] = reward
+			h.OnRewardUpdate.Emit(reward)
+		case helix.EventSubTypeChannelPointsCustomRewardRemove:
+			delete(h.rewardCatalog, reward.ID)
+			h.OnRewardRemove.Emit(reward)
+		}
+		h.OnRewardEvent.Emit(newRewardEvent(meta, reward))
+	case lib.ChatNotificationEventV1:
+		notification, err := chatNotificationFromEvent(event)
+		if err != nil {
+			return err
 		}
 
-		h.OnPollProgress.Emit(title, choicesArray)
-	case helix.EventSubTypeChannelPollEnd:
-		title := h.readStringFromEvent(eventMsg.Payload.Event, "title")
+		h.OnChatNotification.Emit(notification)
+		h.OnChatNotificationEvent.Emit(newChatNotificationEvent(meta, notification))
 
-		choicesArray := h.readPollChoices(eventMsg, false)
-		if choicesArray == nil {
-			return
+		switch helix.EventSubChannelChatNotificationType(event.NoticeType) {
+		case helix.EventSubChannelNotificationCommunitySubGift:
+			var gifterID string
+			if !event.ChatterIsAnonymous {
+				gifterID = event.ChatterUserID
+			}
+			gift := notification.CommunitySubGift
+			h.linkCommunityGift(gift.ID, gifterID, gift.Tier, gift.Total)
+		case helix.EventSubChannelNotificationSubGift:
+			gift := notification.SubGift
+			h.addGiftedSub(lib.GiftedSub{
+				CommunityGiftID: gift.CommunityGiftID,
+				Tier:            gift.Tier,
+				RecipientID:     gift.RecipientUserID,
+				Recipient:       gift.RecipientUserName,
+				ReceivedAt:      time.Now(),
+			})
 		}
-
-		h.OnPollEnd.Emit(title, choicesArray)
-	case helix.EventSubTypeChannelPredictionBegin:
-		title := h.readStringFromEvent(eventMsg.Payload.Event, "title")
-		locksAtStr := h.readStringFromEvent(eventMsg.Payload.Event, "locks_at")
-		locksAt, err := time.Parse(time.RFC3339, locksAtStr)
+	case lib.ChatClearEventV1:
+		clearedAt, err := time.Parse(time.RFC3339, eventMsg.Metadata.Timestamp)
 		if err != nil {
-			lib.LogErr(fmt.Sprintf("error converting timestamp: %s", err.Error()))
-			return
+			return fmt.Errorf("error converting timestamp: %w", err)
 		}
 
-		outcomesArray := h.readPredictionOutComes(eventMsg, true, false)
-		if outcomesArray == nil {
-			return
+		h.OnChatClear.Emit(int(clearedAt.Unix()))
+		h.OnChatClearEvent.Emit(newChatClearEvent(meta, "", "", ""))
+	case lib.ChatClearUserMessagesEventV1:
+		h.OnChatClearUserMessages.Emit(event.TargetUserName, event.TargetUserID)
+		h.OnChatClearEvent.Emit(newChatClearEvent(meta, event.TargetUserID, event.TargetUserLogin, event.TargetUserName))
+	case lib.ChatMessageDeleteEventV1:
+		h.OnChatMessageDelete.Emit(event.TargetUserName, event.TargetUserID, event.MessageID)
+
+		chatClear := newChatClearEvent(meta, event.TargetUserID, event.TargetUserLogin, event.TargetUserName)
+		chatClear.DeletedMessageID = event.MessageID
+		h.OnChatClearEvent.Emit(chatClear)
+	case lib.ChannelPointsAutomaticRedemptionEventV2:
+		var messageText string
+		if event.Message != nil {
+			messageText = event.Message.Text
 		}
 
-		h.OnPredictionBegin.Emit(title, int(locksAt.Unix()), outcomesArray)
-	case helix.EventSubTypeChannelPredictionProgress:
-		title := h.readStringFromEvent(eventMsg.Payload.Event, "title")
-
-		outcomesArray := h.readPredictionOutComes(eventMsg, false, false)
-		if outcomesArray == nil {
-			return
+		var emoteID string
+		if event.Reward.Emote != nil {
+			emoteID = event.Reward.Emote.ID
+			h.preloadEmote(emoteID)
 		}
 
-		h.OnPredictionProgress.Emit(title, outcomesArray)
-	case helix.EventSubTypeChannelPredictionLock:
-		title := h.readStringFromEvent(eventMsg.Payload.Event, "title")
-
-		outcomesArray := h.readPredictionOutComes(eventMsg, false, false)
-		if outcomesArray == nil {
-			return
+		h.OnAutomaticReward.Emit(event.Reward.Type, event.UserName, event.Reward.ChannelPoints, messageText, emoteID)
+		automaticReward := newAutomaticRewardEvent(meta, event)
+		h.OnAutomaticRewardEvent.Emit(automaticReward)
+		h.pushAlert(AlertTypeAutomaticReward, automaticReward)
+	case lib.ShoutoutCreateEventV1:
+		profilePicUrl := enrichment.profilePictureURL
+		lastGameName := enrichment.lastStreamGame
+		lastStreamTitle := enrichment.lastStreamTitle
+
+		h.OnShoutoutCreate.Emit(event.ToBroadcasterUserName, profilePicUrl, lastGameName, lastStreamTitle)
+
+		shoutout := newShoutoutEvent(meta, lib.BroadcasterRef{
+			BroadcasterUserID:    event.ToBroadcasterUserID,
+			BroadcasterUserLogin: event.ToBroadcasterUserLogin,
+			BroadcasterUserName:  event.ToBroadcasterUserName,
+		}, event.ViewerCount, event.StartedAt, enrichment)
+		shoutout.IsOutgoing = true
+		shoutout.ModeratorUserID = event.ModeratorUserID
+		shoutout.ModeratorUserLogin = event.ModeratorUserLogin
+		shoutout.ModeratorUserName = event.ModeratorUserName
+		h.OnShoutoutEvent.Emit(shoutout)
+	case lib.ShoutoutReceiveEventV1:
+		profilePicUrl := enrichment.profilePictureURL
+
+		h.OnShoutoutReceived.Emit(event.FromBroadcasterUserName, profilePicUrl, event.ViewerCount, unixTime(event.StartedAt))
+
+		shoutout := newShoutoutEvent(meta, lib.BroadcasterRef{
+			BroadcasterUserID:    event.FromBroadcasterUserID,
+			BroadcasterUserLogin: event.FromBroadcasterUserLogin,
+			BroadcasterUserName:  event.FromBroadcasterUserName,
+		}, event.ViewerCount, event.StartedAt, enrichment)
+		h.OnShoutoutEvent.Emit(shoutout)
+		h.pushAlert(AlertTypeShoutout, shoutout)
+	case lib.CharityDonationEventV1:
+		amount := minorUnitsToFloat(event.Amount.Value, event.Amount.DecimalPlaces)
+
+		h.OnDonation.Emit(event.UserName, amount, event.Amount.Currency)
+
+		var donation CharityDonation
+		donation.ID = event.ID
+		donation.CampaignID = event.CampaignID
+		donation.UserID = event.UserID
+		donation.UserLogin = event.UserLogin
+		donation.UserName = event.UserName
+		donation.CharityName = event.CharityName
+		donation.Value = event.Amount.Value
+		donation.DecimalPlaces = event.Amount.DecimalPlaces
+		donation.Currency = event.Amount.Currency
+		donation.Amount = amount
+
+		h.OnCharityDonation.Emit(donation)
+		donationEvent := newDonationEvent(meta, donation)
+		h.OnDonationEvent.Emit(donationEvent)
+		h.pushAlert(AlertTypeDonation, donationEvent)
+	case lib.CharityCampaignEventV1:
+		campaign := charityCampaignFromEvent(event)
+		campaign.IsActive = subType != helix.EventSubTypeCharityStop
+
+		h.CharityCampaign = campaign
+
+		switch subType {
+		case helix.EventSubTypeCharityStart:
+			h.OnCharityCampaignStart.Emit(campaign)
+		case helix.EventSubTypeCharityProgress:
+			h.OnCharityCampaignProgress.Emit(campaign)
+		case helix.EventSubTypeCharityStop:
+			h.OnCharityCampaignStop.Emit(campaign)
 		}
-
-		h.OnPredictionLock.Emit(title, outcomesArray)
-	case helix.EventSubTypeChannelPredictionEnd:
-		title := h.readStringFromEvent(eventMsg.Payload.Event, "title")
-
-		outcomesArray := h.readPredictionOutComes(eventMsg, false, true)
-		if outcomesArray == nil {
-			return
+		h.OnCharityCampaignEvent.Emit(newCharityCampaignEvent(meta, campaign))
+	case lib.ChannelBanEventV1:
+		var duration int
+		if !event.IsPermanent {
+			duration = int(event.EndsAt.Sub(event.BannedAt).Seconds())
 		}
 
-		h.OnPredictionEnd.Emit(title, outcomesArray)
+		h.OnBan.Emit(event.UserName, event.UserID, event.ModeratorUserName, event.ModeratorUserID, event.Reason, duration)
+
+		ban := newBanEvent(meta, event.UserRef, event.ModeratorRef)
+		ban.IsBan = true
+		ban.Reason = event.Reason
+		ban.IsPermanent = event.IsPermanent
+		ban.Duration = duration
+		ban.UnixBannedAt = unixTime(event.BannedAt)
+		ban.UnixEndsAt = unixTime(event.EndsAt)
+		h.OnBanEvent.Emit(ban)
+	case lib.ChannelUnbanEventV1:
+		h.OnUnban.Emit(event.UserName, event.UserID, event.ModeratorUserName, event.ModeratorUserID)
+		h.OnBanEvent.Emit(newBanEvent(meta, event.UserRef, event.ModeratorRef))
+	case lib.ChannelModeratorAddEventV1:
+		h.OnModeratorAdd.Emit(event.UserName, event.UserID)
+		h.OnRoleEvent.Emit(newRoleEvent(meta, event.UserRef, "moderator", true))
+	case lib.ChannelModeratorRemoveEventV1:
+		h.OnModeratorRemove.Emit(event.UserName, event.UserID)
+		h.OnRoleEvent.Emit(newRoleEvent(meta, event.UserRef, "moderator", false))
+	case lib.ChannelVIPAddEventV1:
+		h.OnVIPAdd.Emit(event.UserName, event.UserID)
+		h.OnRoleEvent.Emit(newRoleEvent(meta, event.UserRef, "vip", true))
+	case lib.ChannelVIPRemoveEventV1:
+		h.OnVIPRemove.Emit(event.UserName, event.UserID)
+		h.OnRoleEvent.Emit(newRoleEvent(meta, event.UserRef, "vip", false))
+	case lib.ChannelPollEventV1:
+		switch subType {
+		case helix.EventSubTypeChannelPollBegin:
+			h.ActivePoll = pollFromEvent(event, pollChoicesFromEvent(event, true), true)
+			h.OnPollBegin.Emit(event.Title, unixTime(event.EndsAt), pollChoicesFromEvent(event, true))
+			h.OnPollEvent.Emit(newPollEvent(meta, event, pollChoicesFromEvent(event, true)))
+		case helix.EventSubTypeChannelPollProgress:
+			h.ActivePoll = pollFromEvent(event, pollChoicesFromEvent(event, false), true)
+			h.OnPollProgress.Emit(event.Title, pollChoicesFromEvent(event, false))
+			h.OnPollEvent.Emit(newPollEvent(meta, event, pollChoicesFromEvent(event, false)))
+		case helix.EventSubTypeChannelPollEnd:
+			h.ActivePoll = pollFromEvent(event, pollChoicesFromEvent(event, false), false)
+			h.OnPollEnd.Emit(event.Title, pollChoicesFromEvent(event, false))
+			h.OnPollEvent.Emit(newPollEvent(meta, event, pollChoicesFromEvent(event, false)))
+		}
+	case lib.ChannelPredictionEventV1:
+		switch subType {
+		case helix.EventSubTypeChannelPredictionBegin:
+			h.ActivePrediction = predictionFromEvent(event, predictionOutcomesFromEvent(event, true, false), "active")
+			h.OnPredictionBegin.Emit(event.Title, unixTime(event.LocksAt), predictionOutcomesFromEvent(event, true, false))
+			h.OnPredictionEvent.Emit(newPredictionEvent(meta, event, predictionOutcomesFromEvent(event, true, false)))
+		case helix.EventSubTypeChannelPredictionProgress:
+			h.ActivePrediction = predictionFromEvent(event, predictionOutcomesFromEvent(event, false, false), "active")
+			h.OnPredictionProgress.Emit(event.Title, predictionOutcomesFromEvent(event, false, false))
+			h.OnPredictionEvent.Emit(newPredictionEvent(meta, event, predictionOutcomesFromEvent(event, false, false)))
+		case helix.EventSubTypeChannelPredictionLock:
+			h.ActivePrediction = predictionFromEvent(event, predictionOutcomesFromEvent(event, false, false), "locked")
+			h.OnPredictionLock.Emit(event.Title, predictionOutcomesFromEvent(event, false, false))
+			h.OnPredictionEvent.Emit(newPredictionEvent(meta, event, predictionOutcomesFromEvent(event, false, false)))
+		case helix.EventSubTypeChannelPredictionEnd:
+			h.ActivePrediction = predictionFromEvent(event, predictionOutcomesFromEvent(event, false, true), event.Status)
+			h.OnPredictionEnd.Emit(event.Title, predictionOutcomesFromEvent(event, false, true))
+			h.OnPredictionEvent.Emit(newPredictionEvent(meta, event, predictionOutcomesFromEvent(event, false, true)))
+		}
+	case lib.StreamOnlineEventV1:
+		h.IsLive = true
+		h.StreamStartedAt = unixTime(event.StartedAt)
+		h.streamStateFromEvent = true
+
+		h.OnStreamOnline.Emit(event.Type, h.StreamStartedAt)
+
+		stream := newStreamEvent(meta, true, h.StreamStartedAt)
+		stream.StreamID = event.ID
+		stream.StreamType = event.Type
+		h.OnStreamEvent.Emit(stream)
+	case lib.StreamOfflineEventV1:
+		startedAt := h.StreamStartedAt
+
+		h.IsLive = false
+		h.StreamStartedAt = 0
+		h.streamStateFromEvent = true
+
+		h.OnStreamOffline.Emit(startedAt)
+		h.OnStreamEvent.Emit(newStreamEvent(meta, false, startedAt))
+	case lib.ChannelUpdateEventV2:
+		h.Title = event.Title
+		h.CategoryID = event.CategoryID
+		h.CategoryName = event.CategoryName
+		h.channelInfoFromEvent = true
+
+		h.OnChannelUpdate.Emit(event.Title, event.CategoryID, event.CategoryName)
+		h.OnChannelUpdateEvent.Emit(newChannelUpdateEvent(meta, event))
 	}
+
+	return nil
 }
 
 func (h *GodotTwitch) handleApiUpdateTick() {
+	// signal handlers may start requests that queue their responses
 	h.apiInfoResponseLock.Lock()
-	defer h.apiInfoResponseLock.Unlock()
+	queue := h.apiInfoResponseQueue
+	h.apiInfoResponseQueue = nil
+	h.apiInfoResponseLock.Unlock()
 
-	if len(h.apiInfoResponseQueue) <= 0 {
-		return
-	}
-
-	for _, apiInfo := range h.apiInfoResponseQueue {
+	for _, apiInfo := range queue {
 		switch apiInfo := apiInfo.(type) {
 
 		case LatestFollowerUpdate:
-			if h.LatestFollower != "" {
-				lib.LogInfo("non empty follower string on api update. skip api update because event should bee more up to date")
+			// follows received before the api response are already counted by twitch
+			h.FollowerTotal = apiInfo.Total
+			if apiInfo.Username == "" {
+				continue
+			}
+			if h.latestFollowerFromEvent {
+				lib.LogInfo("follower set by event before api update. skip api update because event should be more up to date")
 				continue
 			}
 
 			h.LatestFollower = apiInfo.Username
 
 		case LatestSubscriberUpdate:
-			if h.LatestSubscriber != "" {
-				lib.LogInfo("non empty follower string on api update. skip api update because event should bee more up to date")
+			h.SubscriberTotal = apiInfo.Total
+			h.SubPoints = apiInfo.Points
+			if apiInfo.Username == "" {
+				continue
+			}
+			if h.latestSubscriberFromEvent {
+				lib.LogInfo("subscriber set by event before api update. skip api update because event should be more up to date")
 				continue
 			}
 			h.LatestSubscriber = apiInfo.Username
+
+		case RewardCatalogUpdate:
+			// rewards changed by events before the api response arrived are more up to date
+			for _, reward := range apiInfo.Rewards {
+				if _, known := h.rewardCatalog[reward.ID]; known {
+					continue
+				}
+				h.rewardCatalog[reward.ID] = reward
+			}
+
+		case CharityCampaignUpdate:
+			if h.CharityCampaign.ID != "" {
+				lib.LogInfo("non empty charity campaign on api update. skip api update because event should be more up to date")
+				continue
+			}
+			h.CharityCampaign = apiInfo.Campaign
+
+		case StreamStateUpdate:
+			if h.streamStateFromEvent {
+				lib.LogInfo("stream state set by event before api update. skip api update because event should be more up to date")
+				continue
+			}
+			h.IsLive = apiInfo.IsLive
+			h.StreamStartedAt = apiInfo.StartedAt
+			h.checkSessionStatsStream()
+
+		case ActivePollUpdate:
+			if h.ActivePoll.ID != "" {
+				lib.LogInfo("poll set by event before api update. skip api update because event should be more up to date")
+				continue
+			}
+			h.ActivePoll = apiInfo.Poll
+
+		case ActivePredictionUpdate:
+			if h.ActivePrediction.ID != "" {
+				lib.LogInfo("prediction set by event before api update. skip api update because event should be more up to date")
+				continue
+			}
+			h.ActivePrediction = apiInfo.Prediction
+
+		case GoalsUpdate:
+			h.ActiveGoals = apiInfo.Goals
+
+		case HypeTrainUpdate:
+			h.HypeTrain = apiInfo.HypeTrain
+
+		case BitsLeaderboardUpdate:
+			h.finishBitsLeaderboard(apiInfo)
+
+		case PollsResponse:
+			h.OnPolls.Emit(apiInfo.Polls)
+
+		case EventHistoryResponse:
+			h.OnEventHistory.Emit(apiInfo.Type, apiInfo.Entries)
+
+		case JournalRestoreUpdate:
+			if h.LatestFollower == "" {
+				h.LatestFollower = apiInfo.LatestFollower
+			}
+			if h.LatestSubscriber == "" {
+				h.LatestSubscriber = apiInfo.LatestSubscriber
+			}
+
+		case RaidResult:
+			h.raidFinished(apiInfo)
+
+		case PollError:
+			h.pollFailed(apiInfo.Action, apiInfo.Message)
+
+		case PlaybackFinished:
+			h.OnPlaybackFinished.Emit(apiInfo.Path)
+
+		case UserInfoResponse:
+			h.OnUserInfo.Emit(apiInfo.Query, apiInfo.Info)
+
+		case ChannelInfoUpdate:
+			if h.channelInfoFromEvent {
+				lib.LogInfo("channel info set by event before api update. skip api update because event should be more up to date")
+				continue
+			}
+			h.Title = apiInfo.Title
+			h.CategoryID = apiInfo.CategoryID
+			h.CategoryName = apiInfo.CategoryName
 		}
 	}
-	h.apiInfoResponseQueue = make([]interface{}, 0)
 }
@@ -0,0 +1,725 @@
+package node
+
+import (
+	"main/lib"
+	"time"
+
+	"graphics.gd/classdb"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Object"
+	"graphics.gd/variant/RefCounted"
+)
+
+// Every event object carries the subscription type it was created from and the id and send time of
+// the websocket message, so scripts can tell apart e.g. a redemption add from an update.
+
+type TwitchFollowEvent struct {
+	classdb.Extension[TwitchFollowEvent, RefCounted.Instance] `gd:"TwitchFollowEvent"
+		Twitch Event: channel.follow`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	UserID         string `gd:"user_id"`
+	UserLogin      string `gd:"user_login"`
+	UserName       string `gd:"user_name"`
+	UnixFollowedAt int    `gd:"unix_followed_at"`
+}
+
+type TwitchSubscriptionEvent struct {
+	classdb.Extension[TwitchSubscriptionEvent, RefCounted.Instance] `gd:"TwitchSubscriptionEvent"
+		Twitch Event: channel.subscribe and channel.subscription.message, tier is 1000, 2000 or 3000. Message fields are only set for resubs`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	UserID           string `gd:"user_id"`
+	UserLogin        string `gd:"user_login"`
+	UserName         string `gd:"user_name"`
+	Tier             int    `gd:"tier"`
+	IsGift           bool   `gd:"is_gift"`
+	IsResub          bool   `gd:"is_resub"`
+	CumulativeMonths int    `gd:"cumulative_months"`
+	StreakMonths     int    `gd:"streak_months"
+		Zero if the user does not share their streak`
+	DurationMonths int `gd:"duration_months"
+		Months the user subscribed for with this subscription`
+	MessageText      string            `gd:"message_text"`
+	MessageFragments []MessageFragment `gd:"message_fragments"
+		message_text split into text and emote fragments, emote_id can be loaded with GodotTwitchEmoteStore`
+}
+
+type TwitchGiftSubEvent struct {
+	classdb.Extension[TwitchGiftSubEvent, RefCounted.Instance] `gd:"TwitchGiftSubEvent"
+		Twitch Event: channel.subscription.gift, user fields and cumulative_total are empty for anonymous gifts`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	UserID          string `gd:"user_id"`
+	UserLogin       string `gd:"user_login"`
+	UserName        string `gd:"user_name"`
+	IsAnonymous     bool   `gd:"is_anonymous"`
+	Tier            int    `gd:"tier"`
+	Total           int    `gd:"total"`
+	CumulativeTotal int    `gd:"cumulative_total"`
+}
+
+type TwitchCheerEvent struct {
+	classdb.Extension[TwitchCheerEvent, RefCounted.Instance] `gd:"TwitchCheerEvent"
+		Twitch Event: channel.cheer, user fields are empty for anonymous cheers`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	UserID      string `gd:"user_id"`
+	UserLogin   string `gd:"user_login"`
+	UserName    string `gd:"user_name"`
+	IsAnonymous bool   `gd:"is_anonymous"`
+	Bits        int    `gd:"bits"`
+	MessageText string `gd:"message_text"`
+}
+
+type TwitchRaidEvent struct {
+	classdb.Extension[TwitchRaidEvent, RefCounted.Instance] `gd:"TwitchRaidEvent"
+		Twitch Event: channel.raid, profile_picture_url is only set for incoming raids`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	IsOutgoing               bool   `gd:"is_outgoing"`
+	FromBroadcasterUserID    string `gd:"from_broadcaster_user_id"`
+	FromBroadcasterUserLogin string `gd:"from_broadcaster_user_login"`
+	FromBroadcasterUserName  string `gd:"from_broadcaster_user_name"`
+	ToBroadcasterUserID      string `gd:"to_broadcaster_user_id"`
+	ToBroadcasterUserLogin   string `gd:"to_broadcaster_user_login"`
+	ToBroadcasterUserName    string `gd:"to_broadcaster_user_name"`
+	Viewers                  int    `gd:"viewers"`
+	ProfilePictureURL        string `gd:"profile_picture_url"`
+}
+
+type TwitchRedemptionEvent struct {
+	classdb.Extension[TwitchRedemptionEvent, RefCounted.Instance] `gd:"TwitchRedemptionEvent"
+		Twitch Event: channel.channel_points_custom_reward_redemption.add and .update`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	ID             string `gd:"id"`
+	UserID         string `gd:"user_id"`
+	UserLogin      string `gd:"user_login"`
+	UserName       string `gd:"user_name"`
+	UserInput      string `gd:"user_input"`
+	Status         string `gd:"status"`
+	UnixRedeemedAt int    `gd:"unix_redeemed_at"`
+	RewardID       string `gd:"reward_id"`
+	RewardTitle    string `gd:"reward_title"`
+	RewardPrompt   string `gd:"reward_prompt"`
+	RewardCost     int    `gd:"reward_cost"`
+}
+
+type TwitchAutomaticRewardEvent struct {
+	classdb.Extension[TwitchAutomaticRewardEvent, RefCounted.Instance] `gd:"TwitchAutomaticRewardEvent"
+		Twitch Event: channel.channel_points_automatic_reward_redemption.add`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	ID             string `gd:"id"`
+	UserID         string `gd:"user_id"`
+	UserLogin      string `gd:"user_login"`
+	UserName       string `gd:"user_name"`
+	RewardType     string `gd:"reward_type"`
+	Cost           int    `gd:"cost"`
+	MessageText    string `gd:"message_text"`
+	EmoteID        string `gd:"emote_id"`
+	EmoteName      string `gd:"emote_name"`
+	UnixRedeemedAt int    `gd:"unix_redeemed_at"`
+}
+
+type TwitchShoutoutEvent struct {
+	classdb.Extension[TwitchShoutoutEvent, RefCounted.Instance] `gd:"TwitchShoutoutEvent"
+		Twitch Event: channel.shoutout.create and channel.shoutout.receive, broadcaster fields describe the other channel`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	IsOutgoing           bool   `gd:"is_outgoing"`
+	BroadcasterUserID    string `gd:"broadcaster_user_id"`
+	BroadcasterUserLogin string `gd:"broadcaster_user_login"`
+	BroadcasterUserName  string `gd:"broadcaster_user_name"`
+	ProfilePictureURL    string `gd:"profile_picture_url"`
+	ModeratorUserID      string `gd:"moderator_user_id"`
+	ModeratorUserLogin   string `gd:"moderator_user_login"`
+	ModeratorUserName    string `gd:"moderator_user_name"`
+	ViewerCount          int    `gd:"viewer_count"`
+	UnixStartedAt        int    `gd:"unix_started_at"`
+	LastStreamGame       string `gd:"last_stream_game"`
+	LastStreamTitle      string `gd:"last_stream_title"`
+}
+
+type TwitchDonationEvent struct {
+	classdb.Extension[TwitchDonationEvent, RefCounted.Instance] `gd:"TwitchDonationEvent"
+		Twitch Event: channel.charity_campaign.donate, value is in minor units and amount is converted for display`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	ID            string  `gd:"id"`
+	CampaignID    string  `gd:"campaign_id"`
+	UserID        string  `gd:"user_id"`
+	UserLogin     string  `gd:"user_login"`
+	UserName      string  `gd:"user_name"`
+	CharityName   string  `gd:"charity_name"`
+	Value         int     `gd:"value"`
+	DecimalPlaces int     `gd:"decimal_places"`
+	Currency      string  `gd:"currency"`
+	Amount        Float.X `gd:"amount"`
+}
+
+type TwitchBanEvent struct {
+	classdb.Extension[TwitchBanEvent, RefCounted.Instance] `gd:"TwitchBanEvent"
+		Twitch Event: channel.ban and channel.unban, ban fields are empty for unbans`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	IsBan              bool   `gd:"is_ban"`
+	UserID             string `gd:"user_id"`
+	UserLogin          string `gd:"user_login"`
+	UserName           string `gd:"user_name"`
+	ModeratorUserID    string `gd:"moderator_user_id"`
+	ModeratorUserLogin string `gd:"moderator_user_login"`
+	ModeratorUserName  string `gd:"moderator_user_name"`
+	Reason             string `gd:"reason"`
+	IsPermanent        bool   `gd:"is_permanent"`
+	Duration           int    `gd:"duration"`
+	UnixBannedAt       int    `gd:"unix_banned_at"`
+	UnixEndsAt         int    `gd:"unix_ends_at"`
+}
+
+type TwitchRoleEvent struct {
+	classdb.Extension[TwitchRoleEvent, RefCounted.Instance] `gd:"TwitchRoleEvent"
+		Twitch Event: channel.moderator.add/remove and channel.vip.add/remove, role is moderator or vip`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	Role      string `gd:"role"`
+	IsAdded   bool   `gd:"is_added"`
+	UserID    string `gd:"user_id"`
+	UserLogin string `gd:"user_login"`
+	UserName  string `gd:"user_name"`
+}
+
+type TwitchPollEvent struct {
+	classdb.Extension[TwitchPollEvent, RefCounted.Instance] `gd:"TwitchPollEvent"
+		Twitch Event: channel.poll.begin, .progress and .end, votes are empty on begin`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	ID            string   `gd:"id"`
+	Title         string   `gd:"title"`
+	Status        string   `gd:"status"`
+	Choices       []Choice `gd:"choices"`
+	UnixStartedAt int      `gd:"unix_started_at"`
+	UnixEndsAt    int      `gd:"unix_ends_at"`
+	UnixEndedAt   int      `gd:"unix_ended_at"`
+}
+
+type TwitchPredictionEvent struct {
+	classdb.Extension[TwitchPredictionEvent, RefCounted.Instance] `gd:"TwitchPredictionEvent"
+		Twitch Event: channel.prediction.begin, .progress, .lock and .end, winning_outcome_id is only set on end`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	ID               string              `gd:"id"`
+	Title            string              `gd:"title"`
+	Status           string              `gd:"status"`
+	WinningOutcomeID string              `gd:"winning_outcome_id"`
+	Outcomes         []PredictionOutcome `gd:"outcomes"`
+	UnixStartedAt    int                 `gd:"unix_started_at"`
+	UnixLocksAt      int                 `gd:"unix_locks_at"`
+	UnixLockedAt     int                 `gd:"unix_locked_at"`
+	UnixEndedAt      int                 `gd:"unix_ended_at"`
+}
+
+type TwitchStreamEvent struct {
+	classdb.Extension[TwitchStreamEvent, RefCounted.Instance] `gd:"TwitchStreamEvent"
+		Twitch Event: stream.online and stream.offline, unix_started_at is the start of the ended stream on offline`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	IsLive        bool   `gd:"is_live"`
+	StreamID      string `gd:"stream_id"`
+	StreamType    string `gd:"stream_type"`
+	UnixStartedAt int    `gd:"unix_started_at"`
+}
+
+type TwitchChannelUpdateEvent struct {
+	classdb.Extension[TwitchChannelUpdateEvent, RefCounted.Instance] `gd:"TwitchChannelUpdateEvent"
+		Twitch Event: channel.update`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	Title        string `gd:"title"`
+	Language     string `gd:"language"`
+	CategoryID   string `gd:"category_id"`
+	CategoryName string `gd:"category_name"`
+}
+
+type TwitchChatNotificationEvent struct {
+	classdb.Extension[TwitchChatNotificationEvent, RefCounted.Instance] `gd:"TwitchChatNotificationEvent"
+		Twitch Event: channel.chat.notification, only the notice matching notice_type is filled`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	NoticeType         string            `gd:"notice_type"`
+	ChatterUserID      string            `gd:"chatter_user_id"`
+	ChatterUserLogin   string            `gd:"chatter_user_login"`
+	ChatterUserName    string            `gd:"chatter_user_name"`
+	ChatterIsAnonymous bool              `gd:"chatter_is_anonymous"`
+	Color              string            `gd:"color"`
+	SystemMessage      string            `gd:"system_message"`
+	ChatMessageID      string            `gd:"chat_message_id"`
+	MessageText        string            `gd:"message_text"`
+	MessageFragments   []MessageFragment `gd:"message_fragments"`
+
+	Sub              SubNotice              `gd:"sub"`
+	Resub            ResubNotice            `gd:"resub"`
+	SubGift          SubGiftNotice          `gd:"sub_gift"`
+	CommunitySubGift CommunitySubGiftNotice `gd:"community_sub_gift"`
+	GiftPaidUpgrade  GifterNotice           `gd:"gift_paid_upgrade"`
+	PrimePaidUpgrade PrimePaidUpgradeNotice `gd:"prime_paid_upgrade"`
+	Raid             RaidNotice             `gd:"raid"`
+	PayItForward     GifterNotice           `gd:"pay_it_forward"`
+	Announcement     AnnouncementNotice     `gd:"announcement"`
+	BitsBadgeTier    BitsBadgeTierNotice    `gd:"bits_badge_tier"`
+	CharityDonation  CharityDonationNotice  `gd:"charity_donation"`
+}
+
+type TwitchChatClearEvent struct {
+	classdb.Extension[TwitchChatClearEvent, RefCounted.Instance] `gd:"TwitchChatClearEvent"
+		Twitch Event: channel.chat.clear, .clear_user_messages and .message_delete, target fields are empty for clear and deleted_message_id is only set for message_delete`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	TargetUserID     string `gd:"target_user_id"`
+	TargetUserLogin  string `gd:"target_user_login"`
+	TargetUserName   string `gd:"target_user_name"`
+	DeletedMessageID string `gd:"deleted_message_id"`
+}
+
+type TwitchRewardEvent struct {
+	classdb.Extension[TwitchRewardEvent, RefCounted.Instance] `gd:"TwitchRewardEvent"
+		Twitch Event: channel.channel_points_custom_reward.add, .update and .remove`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	ID                  string `gd:"id"`
+	Title               string `gd:"title"`
+	Prompt              string `gd:"prompt"`
+	Cost                int    `gd:"cost"`
+	BackgroundColor     string `gd:"background_color"`
+	IsEnabled           bool   `gd:"is_enabled"`
+	IsPaused            bool   `gd:"is_paused"`
+	IsInStock           bool   `gd:"is_in_stock"`
+	IsUserInputRequired bool   `gd:"is_user_input_required"`
+}
+
+type TwitchCharityCampaignEvent struct {
+	classdb.Extension[TwitchCharityCampaignEvent, RefCounted.Instance] `gd:"TwitchCharityCampaignEvent"
+		Twitch Event: channel.charity_campaign.start, .progress and .stop, values are in minor units and amounts are converted for display`
+
+	Type       string `gd:"type"`
+	MessageID  string `gd:"message_id"`
+	UnixSentAt int    `gd:"unix_sent_at"`
+
+	ID                 string  `gd:"id"`
+	IsActive           bool    `gd:"is_active"`
+	CharityName        string  `gd:"charity_name"`
+	CharityDescription string  `gd:"charity_description"`
+	CharityLogoURL     string  `gd:"charity_logo_url"`
+	CharityWebsite     string  `gd:"charity_website"`
+	CurrentValue       int     `gd:"current_value"`
+	TargetValue        int     `gd:"target_value"`
+	DecimalPlaces      int     `gd:"decimal_places"`
+	Currency           string  `gd:"currency"`
+	CurrentAmount      Float.X `gd:"current_amount"`
+	TargetAmount       Float.X `gd:"target_amount"`
+}
+
+// eventMeta holds the notification fields every event object starts with.
+type eventMeta struct {
+	subType    string
+	messageID  string
+	unixSentAt int
+}
+
+func eventMetaFrom(eventMsg lib.TwitchMessage) eventMeta {
+	var meta eventMeta
+	meta.subType = eventMsg.Payload.Subscription.Type
+	meta.messageID = eventMsg.Metadata.ID
+
+	// a missing or broken timestamp should not drop the event
+	if sentAt, err := time.Parse(time.RFC3339, eventMsg.Metadata.Timestamp); err == nil {
+		meta.unixSentAt = unixTime(sentAt)
+	}
+
+	return meta
+}
+
+func newFollowEvent(meta eventMeta, event lib.ChannelFollowEventV2) *TwitchFollowEvent {
+	obj := new(TwitchFollowEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.UserID = event.UserID
+	obj.UserLogin = event.UserLogin
+	obj.UserName = event.UserName
+	obj.UnixFollowedAt = unixTime(event.FollowedAt)
+
+	return obj
+}
+
+func newSubscriptionEvent(meta eventMeta, user lib.UserRef, tier int) *TwitchSubscriptionEvent {
+	obj := new(TwitchSubscriptionEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.UserID = user.UserID
+	obj.UserLogin = user.UserLogin
+	obj.UserName = user.UserName
+	obj.Tier = tier
+
+	return obj
+}
+
+func newGiftSubEvent(meta eventMeta, event lib.ChannelSubscriptionGiftEventV1, tier int) *TwitchGiftSubEvent {
+	obj := new(TwitchGiftSubEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.IsAnonymous = event.IsAnonymous
+	if !event.IsAnonymous {
+		obj.UserID = event.UserID
+		obj.UserLogin = event.UserLogin
+		obj.UserName = event.UserName
+		obj.CumulativeTotal = event.CumulativeTotal
+	}
+	obj.Tier = tier
+	obj.Total = event.Total
+
+	return obj
+}
+
+func newCheerEvent(meta eventMeta, event lib.ChannelCheerEventV1) *TwitchCheerEvent {
+	obj := new(TwitchCheerEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.IsAnonymous = event.IsAnonymous
+	if !event.IsAnonymous {
+		obj.UserID = event.UserID
+		obj.UserLogin = event.UserLogin
+		obj.UserName = event.UserName
+	}
+	obj.Bits = event.Bits
+	obj.MessageText = event.Message
+
+	return obj
+}
+
+func newRaidEvent(meta eventMeta, event lib.ChannelRaidEventV1, isOutgoing bool) *TwitchRaidEvent {
+	obj := new(TwitchRaidEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.IsOutgoing = isOutgoing
+	obj.FromBroadcasterUserID = event.FromBroadcasterUserID
+	obj.FromBroadcasterUserLogin = event.FromBroadcasterUserLogin
+	obj.FromBroadcasterUserName = event.FromBroadcasterUserName
+	obj.ToBroadcasterUserID = event.ToBroadcasterUserID
+	obj.ToBroadcasterUserLogin = event.ToBroadcasterUserLogin
+	obj.ToBroadcasterUserName = event.ToBroadcasterUserName
+	obj.Viewers = event.Viewers
+
+	return obj
+}
+
+func newRedemptionEvent(meta eventMeta, redemption Redemption) *TwitchRedemptionEvent {
+	obj := new(TwitchRedemptionEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.ID = redemption.ID
+	obj.UserID = redemption.UserID
+	obj.UserLogin = redemption.UserLogin
+	obj.UserName = redemption.UserName
+	obj.UserInput = redemption.UserInput
+	obj.Status = redemption.Status
+	obj.UnixRedeemedAt = redemption.UnixRedeemedAt
+	obj.RewardID = redemption.RewardID
+	obj.RewardTitle = redemption.RewardTitle
+	obj.RewardPrompt = redemption.RewardPrompt
+	obj.RewardCost = redemption.RewardCost
+
+	return obj
+}
+
+func newAutomaticRewardEvent(meta eventMeta, event lib.ChannelPointsAutomaticRedemptionEventV2) *TwitchAutomaticRewardEvent {
+	obj := new(TwitchAutomaticRewardEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.ID = event.ID
+	obj.UserID = event.UserID
+	obj.UserLogin = event.UserLogin
+	obj.UserName = event.UserName
+	obj.RewardType = event.Reward.Type
+	obj.Cost = event.Reward.ChannelPoints
+	if event.Message != nil {
+		obj.MessageText = event.Message.Text
+	}
+	if event.Reward.Emote != nil {
+		obj.EmoteID = event.Reward.Emote.ID
+		obj.EmoteName = event.Reward.Emote.Name
+	}
+	obj.UnixRedeemedAt = unixTime(event.RedeemedAt)
+
+	return obj
+}
+
+// newShoutoutEvent takes the fields shared by created and received shoutouts, the broadcaster is the
+// other channel.
+func newShoutoutEvent(meta eventMeta, broadcaster lib.BroadcasterRef, viewerCount int, startedAt time.Time, enrichment eventEnrichment) *TwitchShoutoutEvent {
+	obj := new(TwitchShoutoutEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.BroadcasterUserID = broadcaster.BroadcasterUserID
+	obj.BroadcasterUserLogin = broadcaster.BroadcasterUserLogin
+	obj.BroadcasterUserName = broadcaster.BroadcasterUserName
+	obj.ProfilePictureURL = enrichment.profilePictureURL
+	obj.ViewerCount = viewerCount
+	obj.UnixStartedAt = unixTime(startedAt)
+	obj.LastStreamGame = enrichment.lastStreamGame
+	obj.LastStreamTitle = enrichment.lastStreamTitle
+
+	return obj
+}
+
+func newDonationEvent(meta eventMeta, donation CharityDonation) *TwitchDonationEvent {
+	obj := new(TwitchDonationEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.ID = donation.ID
+	obj.CampaignID = donation.CampaignID
+	obj.UserID = donation.UserID
+	obj.UserLogin = donation.UserLogin
+	obj.UserName = donation.UserName
+	obj.CharityName = donation.CharityName
+	obj.Value = donation.Value
+	obj.DecimalPlaces = donation.DecimalPlaces
+	obj.Currency = donation.Currency
+	obj.Amount = donation.Amount
+
+	return obj
+}
+
+func newBanEvent(meta eventMeta, user lib.UserRef, moderator lib.ModeratorRef) *TwitchBanEvent {
+	obj := new(TwitchBanEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.UserID = user.UserID
+	obj.UserLogin = user.UserLogin
+	obj.UserName = user.UserName
+	obj.ModeratorUserID = moderator.ModeratorUserID
+	obj.ModeratorUserLogin = moderator.ModeratorUserLogin
+	obj.ModeratorUserName = moderator.ModeratorUserName
+
+	return obj
+}
+
+func newRoleEvent(meta eventMeta, user lib.UserRef, role string, isAdded bool) *TwitchRoleEvent {
+	obj := new(TwitchRoleEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.Role = role
+	obj.IsAdded = isAdded
+	obj.UserID = user.UserID
+	obj.UserLogin = user.UserLogin
+	obj.UserName = user.UserName
+
+	return obj
+}
+
+func newPollEvent(meta eventMeta, event lib.ChannelPollEventV1, choices []Choice) *TwitchPollEvent {
+	obj := new(TwitchPollEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.ID = event.ID
+	obj.Title = event.Title
+	obj.Status = event.Status
+	obj.Choices = choices
+	obj.UnixStartedAt = unixTime(event.StartedAt)
+	obj.UnixEndsAt = unixTime(event.EndsAt)
+	obj.UnixEndedAt = unixTime(event.EndedAt)
+
+	return obj
+}
+
+func newPredictionEvent(meta eventMeta, event lib.ChannelPredictionEventV1, outcomes []PredictionOutcome) *TwitchPredictionEvent {
+	obj := new(TwitchPredictionEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.ID = event.ID
+	obj.Title = event.Title
+	obj.Status = event.Status
+	obj.WinningOutcomeID = event.WinningOutcomeID
+	obj.Outcomes = outcomes
+	obj.UnixStartedAt = unixTime(event.StartedAt)
+	obj.UnixLocksAt = unixTime(event.LocksAt)
+	obj.UnixLockedAt = unixTime(event.LockedAt)
+	obj.UnixEndedAt = unixTime(event.EndedAt)
+
+	return obj
+}
+
+func newStreamEvent(meta eventMeta, isLive bool, unixStartedAt int) *TwitchStreamEvent {
+	obj := new(TwitchStreamEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.IsLive = isLive
+	obj.UnixStartedAt = unixStartedAt
+
+	return obj
+}
+
+func newChannelUpdateEvent(meta eventMeta, event lib.ChannelUpdateEventV2) *TwitchChannelUpdateEvent {
+	obj := new(TwitchChannelUpdateEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.Title = event.Title
+	obj.Language = event.Language
+	obj.CategoryID = event.CategoryID
+	obj.CategoryName = event.CategoryName
+
+	return obj
+}
+
+func newChatNotificationEvent(meta eventMeta, notification ChatNotification) *TwitchChatNotificationEvent {
+	obj := new(TwitchChatNotificationEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.NoticeType = notification.Type
+	obj.ChatterUserID = notification.ChatterUserID
+	obj.ChatterUserLogin = notification.ChatterUserLogin
+	obj.ChatterUserName = notification.ChatterUserName
+	obj.ChatterIsAnonymous = notification.ChatterIsAnonymous
+	obj.Color = notification.Color
+	obj.SystemMessage = notification.SystemMessage
+	obj.ChatMessageID = notification.MessageID
+	obj.MessageText = notification.MessageText
+	obj.MessageFragments = notification.MessageFragments
+	obj.Sub = notification.Sub
+	obj.Resub = notification.Resub
+	obj.SubGift = notification.SubGift
+	obj.CommunitySubGift = notification.CommunitySubGift
+	obj.GiftPaidUpgrade = notification.GiftPaidUpgrade
+	obj.PrimePaidUpgrade = notification.PrimePaidUpgrade
+	obj.Raid = notification.Raid
+	obj.PayItForward = notification.PayItForward
+	obj.Announcement = notification.Announcement
+	obj.BitsBadgeTier = notification.BitsBadgeTier
+	obj.CharityDonation = notification.CharityDonation
+
+	return obj
+}
+
+func newChatClearEvent(meta eventMeta, targetUserID string, targetUserLogin string, targetUserName string) *TwitchChatClearEvent {
+	obj := new(TwitchChatClearEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.TargetUserID = targetUserID
+	obj.TargetUserLogin = targetUserLogin
+	obj.TargetUserName = targetUserName
+
+	return obj
+}
+
+func newRewardEvent(meta eventMeta, reward Reward) *TwitchRewardEvent {
+	obj := new(TwitchRewardEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.ID = reward.ID
+	obj.Title = reward.Title
+	obj.Prompt = reward.Prompt
+	obj.Cost = reward.Cost
+	obj.BackgroundColor = reward.BackgroundColor
+	obj.IsEnabled = reward.IsEnabled
+	obj.IsPaused = reward.IsPaused
+	obj.IsInStock = reward.IsInStock
+	obj.IsUserInputRequired = reward.IsUserInputRequired
+
+	return obj
+}
+
+func newCharityCampaignEvent(meta eventMeta, campaign CharityCampaign) *TwitchCharityCampaignEvent {
+	obj := new(TwitchCharityCampaignEvent)
+	obj.Type, obj.MessageID, obj.UnixSentAt = meta.subType, meta.messageID, meta.unixSentAt
+	obj.ID = campaign.ID
+	obj.IsActive = campaign.IsActive
+	obj.CharityName = campaign.CharityName
+	obj.CharityDescription = campaign.CharityDescription
+	obj.CharityLogoURL = campaign.CharityLogoURL
+	obj.CharityWebsite = campaign.CharityWebsite
+	obj.CurrentValue = campaign.CurrentValue
+	obj.TargetValue = campaign.TargetValue
+	obj.DecimalPlaces = campaign.DecimalPlaces
+	obj.Currency = campaign.Currency
+	obj.CurrentAmount = campaign.CurrentAmount
+	obj.TargetAmount = campaign.TargetAmount
+
+	return obj
+}
+
+// alertEvent is implemented by event objects that can be shown as alerts.
+type alertEvent interface {
+	asObject() Object.Instance
+}
+
+func (obj *TwitchFollowEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchSubscriptionEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchGiftSubEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchCheerEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchRaidEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchRedemptionEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchAutomaticRewardEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchShoutoutEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
+
+func (obj *TwitchDonationEvent) asObject() Object.Instance {
+	return Object.Instance(obj.AsObject())
+}
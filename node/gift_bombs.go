@@ -0,0 +1,46 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+	"time"
+)
+
+// startGiftBomb tracks a gift event, the gift alert is pushed once its recipients are known.
+// gifterID and gifter are empty for anonymous gifts.
+func (h *GodotTwitch) startGiftBomb(gifterID string, gifter string, tier int, total int, event *TwitchGiftSubEvent) {
+	h.completeGiftBombs(h.giftBombs.Start(gifterID, gifter, tier, total, event, time.Now()))
+}
+
+func (h *GodotTwitch) linkCommunityGift(id string, gifterID string, tier int, total int) {
+	h.completeGiftBombs(h.giftBombs.LinkCommunityGift(id, gifterID, tier, total, time.Now()))
+}
+
+func (h *GodotTwitch) addGiftedSub(sub lib.GiftedSub) {
+	h.completeGiftBombs(h.giftBombs.AddGiftedSub(sub, time.Now()))
+}
+
+// completeGiftBombs emits on_gift_bomb_complete and pushes the gift alert with the recipients.
+func (h *GodotTwitch) completeGiftBombs(bombs []*lib.GiftBomb[*TwitchGiftSubEvent]) {
+	for _, bomb := range bombs {
+		h.OnGiftBombComplete.Emit(bomb.Gifter, bomb.Tier, bomb.Recipients)
+
+		if alert := h.pushAlert(AlertTypeGiftSub, bomb.Event); alert != nil {
+			alert.Recipients = bomb.Recipients
+		}
+	}
+}
+
+// handleGiftBombTick completes gift bombs whose recipients did not all arrive in time with the
+// recipients known so far.
+func (h *GodotTwitch) handleGiftBombTick() {
+	completed, expiredSubs, expiredGiftIDs := h.giftBombs.Expire(time.Now())
+	h.completeGiftBombs(completed)
+
+	for _, sub := range expiredSubs {
+		lib.LogWarn(fmt.Sprintf("no gift event for gifted sub of %s", sub.Recipient))
+	}
+	for _, id := range expiredGiftIDs {
+		lib.LogWarn(fmt.Sprintf("no gift event for community gift %s", id))
+	}
+}
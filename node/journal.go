@@ -0,0 +1,156 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"main/lib"
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+	"graphics.gd/classdb/ProjectSettings"
+)
+
+const (
+	journalDir      = "user://twitch_journal"
+	journalMaxBytes = 1024 * 1024
+	journalMaxFiles = 5
+)
+
+type HistoryEntry struct {
+	Type             string                 `gd:"type"`
+	Version          string                 `gd:"version"`
+	MessageID        string                 `gd:"message_id"`
+	MessageTimestamp string                 `gd:"message_timestamp"`
+	Event            map[string]interface{} `gd:"event"`
+}
+
+func (h *GodotTwitch) openJournal() {
+	journal, err := lib.NewJournal(ProjectSettings.GlobalizePath(journalDir), journalMaxBytes, journalMaxFiles)
+	if err != nil {
+		lib.LogErr(fmt.Sprintf("unable to open event journal: %s", err.Error()))
+		return
+	}
+	h.journal = journal
+
+	h.restoreFromJournal()
+}
+
+// restoreFromJournal reads the latest-* properties of the last session in the background. Values
+// set by the api or events before it finishes are kept.
+func (h *GodotTwitch) restoreFromJournal() {
+	journal := h.journal
+	go func() {
+		var update JournalRestoreUpdate
+
+		follows, err := journal.Recent(helix.EventSubTypeChannelFollow, 1)
+		if err != nil {
+			lib.LogWarn(fmt.Sprintf("unable to read event journal: %s", err.Error()))
+		}
+		if len(follows) > 0 {
+			var follow lib.ChannelFollowEventV2
+			if err := json.Unmarshal(follows[0].Event, &follow); err == nil {
+				update.LatestFollower = follow.UserName
+			}
+		}
+
+		var latestSubAt time.Time
+		for _, subType := range []string{helix.EventSubTypeChannelSubscription, helix.EventSubTypeChannelSubscriptionMessage} {
+			subs, err := journal.Recent(subType, 1)
+			if err != nil {
+				lib.LogWarn(fmt.Sprintf("unable to read event journal: %s", err.Error()))
+			}
+			if len(subs) <= 0 {
+				continue
+			}
+
+			subAt, err := time.Parse(time.RFC3339, subs[0].MessageTimestamp)
+			if err != nil || subAt.Before(latestSubAt) {
+				continue
+			}
+
+			var sub lib.UserRef
+			if err := json.Unmarshal(subs[0].Event, &sub); err == nil {
+				update.LatestSubscriber = sub.UserName
+				latestSubAt = subAt
+			}
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, update)
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
+
+// journalEvent returns false if the notification is a redelivery of one that is already journaled.
+func (h *GodotTwitch) journalEvent(eventMsg lib.TwitchMessage) bool {
+	if h.journal == nil {
+		return true
+	}
+
+	err := h.journal.Append(lib.JournalEntry{
+		MessageID:        eventMsg.Metadata.ID,
+		MessageTimestamp: eventMsg.Metadata.Timestamp,
+		Type:             eventMsg.Payload.Subscription.Type,
+		Version:          eventMsg.Payload.Subscription.Version,
+		Event:            eventMsg.Payload.Event,
+	})
+	if errors.Is(err, lib.ErrDuplicateEntry) {
+		lib.LogInfo(fmt.Sprintf("skip redelivered notification %s", eventMsg.Metadata.ID))
+		return false
+	}
+	if err != nil {
+		lib.LogErr(fmt.Sprintf("unable to write event journal: %s", err.Error()))
+	}
+
+	return true
+}
+
+func (h *GodotTwitch) closeJournal() {
+	if h.journal == nil {
+		return
+	}
+
+	if err := h.journal.Close(); err != nil {
+		lib.LogErr(fmt.Sprintf("unable to close event journal: %s", err.Error()))
+	}
+	h.journal = nil
+}
+
+// GetEventHistory reads up to limit journaled events of the given type in the background and emits
+// on_event_history with them, newest first. An empty type returns events of all types and a limit
+// of zero returns everything in the journal.
+func (h *GodotTwitch) GetEventHistory(eventType string, limit int) {
+	if h.journal == nil {
+		lib.LogErr("unable to read event journal: journal is not open")
+		return
+	}
+
+	journal := h.journal
+	go func() {
+		entries, err := journal.Recent(eventType, limit)
+		if err != nil {
+			lib.LogErr(fmt.Sprintf("unable to read event journal: %s", err.Error()))
+		}
+
+		update := EventHistoryResponse{Type: eventType}
+		for _, entry := range entries {
+			var rawEvent map[string]interface{}
+			if err := json.Unmarshal(entry.Event, &rawEvent); err != nil {
+				continue
+			}
+
+			update.Entries = append(update.Entries, HistoryEntry{
+				Type:             entry.Type,
+				Version:          entry.Version,
+				MessageID:        entry.MessageID,
+				MessageTimestamp: entry.MessageTimestamp,
+				Event:            toGodotValue(rawEvent).(map[string]interface{}),
+			})
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, update)
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
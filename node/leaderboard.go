@@ -0,0 +1,108 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+const maxBitsLeaderboardCount = 100
+
+var bitsLeaderboardPeriods = map[string]bool{"day": true, "week": true, "month": true, "year": true, "all": true}
+
+type BitsLeaderboardEntry struct {
+	Rank      int    `gd:"rank"`
+	UserID    string `gd:"user_id"`
+	UserLogin string `gd:"user_login"`
+	UserName  string `gd:"user_name"`
+	Score     int    `gd:"score"`
+}
+
+// GetBitsLeaderboard fetches the top count cheerers of the current day, week, month, year or of all
+// time and emits on_bits_leaderboard with the result. count is limited to 100.
+func (h *GodotTwitch) GetBitsLeaderboard(period string, count int) {
+	if !bitsLeaderboardPeriods[period] {
+		lib.LogErr(fmt.Sprintf("unable to get bits leaderboard: unknown period %s", period))
+		return
+	}
+	if h.twitchClient == nil {
+		lib.LogErr("unable to get bits leaderboard: client not ready")
+		return
+	}
+
+	if count <= 0 || count > maxBitsLeaderboardCount {
+		count = maxBitsLeaderboardCount
+	}
+	h.bitsLeaderboardPeriod = period
+	h.bitsLeaderboardCount = count
+	h.bitsLeaderboardRequests++
+
+	client := h.twitchClient
+	go func() {
+		update := BitsLeaderboardUpdate{Period: period}
+
+		leaderboardResp, err := client.GetBitsLeaderboard(&helix.BitsLeaderboardParams{
+			Period: period,
+			Count:  count,
+		})
+		if err == nil {
+			err = helixError(leaderboardResp.ResponseCommon)
+		}
+		if err != nil {
+			update.Err = err
+			h.apiInfoResponseLock.Lock()
+			h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, update)
+			h.apiInfoResponseLock.Unlock()
+			return
+		}
+
+		for _, total := range leaderboardResp.Data.UserBitTotals {
+			update.Entries = append(update.Entries, BitsLeaderboardEntry{
+				Rank:      total.Rank,
+				UserID:    total.UserID,
+				UserLogin: total.UserLogin,
+				UserName:  total.UserName,
+				Score:     total.Score,
+			})
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, update)
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
+
+// refreshBitsLeaderboard repeats the last get_bits_leaderboard call if refresh_bits_leaderboard_on_cheer
+// is enabled. Cheers arriving while a request is running result in a single refresh once it is done.
+func (h *GodotTwitch) refreshBitsLeaderboard() {
+	if !h.RefreshBitsLeaderboardOnCheer || h.bitsLeaderboardPeriod == "" {
+		return
+	}
+
+	if h.bitsLeaderboardRequests > 0 {
+		h.bitsLeaderboardRefreshPending = true
+		return
+	}
+
+	h.GetBitsLeaderboard(h.bitsLeaderboardPeriod, h.bitsLeaderboardCount)
+}
+
+// finishBitsLeaderboard emits the result of a get_bits_leaderboard request and starts the refresh
+// that waited for it.
+func (h *GodotTwitch) finishBitsLeaderboard(update BitsLeaderboardUpdate) {
+	if h.bitsLeaderboardRequests > 0 {
+		h.bitsLeaderboardRequests--
+	}
+
+	if update.Err != nil {
+		lib.LogErr(fmt.Sprintf("unable to get bits leaderboard: %s", update.Err.Error()))
+	} else {
+		h.OnBitsLeaderboard.Emit(update.Period, update.Entries)
+	}
+
+	if h.bitsLeaderboardRefreshPending && h.bitsLeaderboardRequests <= 0 {
+		h.bitsLeaderboardRefreshPending = false
+		h.refreshBitsLeaderboard()
+	}
+}
@@ -1,19 +1,66 @@
 package node
 
 import (
+	"encoding/json"
 	"fmt"
 	"main/lib"
 	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/nicklaw5/helix/v2"
+	"graphics.gd/classdb"
 	"graphics.gd/classdb/FileAccess"
+	"graphics.gd/classdb/Node"
 	"graphics.gd/classdb/OS"
 	"graphics.gd/variant/Float"
 )
 
 func (h *GodotTwitch) Ready() {
+	h.alertSettings = defaultAlertSettings()
+	h.ruleStates = make(map[*TwitchRule]*ruleState)
+	h.rulePatterns = make(map[string]*regexp.Regexp)
+	if h.DefaultAlertDuration <= 0 {
+		h.DefaultAlertDuration = 5
+	}
+
+	h.LatestFollower = ""
+	h.LatestSubscriber = ""
+	h.rewardCatalog = make(map[string]Reward)
+	h.CharityCampaign = CharityCampaign{}
+	h.IsLive = false
+	h.StreamStartedAt = 0
+	h.FollowerTotal = 0
+	h.SubscriberTotal = 0
+	h.SubPoints = 0
+	h.ActivePoll = Poll{}
+	h.ActivePrediction = Prediction{}
+	h.ActiveGoals = nil
+	h.HypeTrain = HypeTrain{}
+	h.Title = ""
+	h.CategoryName = ""
+	h.CategoryID = ""
+	h.latestFollowerFromEvent = false
+	h.latestSubscriberFromEvent = false
+	h.streamStateFromEvent = false
+	h.channelInfoFromEvent = false
+	h.bitsLeaderboardRequests = 0
+	h.bitsLeaderboardRefreshPending = false
+
+	if h.journal == nil {
+		h.openJournal()
+	}
+	if h.sessionStats == nil {
+		h.loadSessionStats()
+	}
+
+	h.apiInfoResponseLock = sync.Mutex{}
+	h.apiInfoResponseQueue = make([]interface{}, 0)
+	h.eventProcessLock = sync.Mutex{}
+	h.eventProcessQueue = make([]*enrichedMessage, 0)
+
 	if h.ClientID == "" || h.ClientSecret == "" {
 		lib.LogErr("missing client id or client secret")
 		return
@@ -34,21 +81,22 @@ func (h *GodotTwitch) Ready() {
 		Scopes: []string{
 			"bits:read",
 			"channel:read:charity", "channel:read:redemptions", "channel:read:ads", "channel:read:subscriptions",
-			"channel:read:polls", "channel:read:predictions", "channel:read:goals",
+			"channel:read:polls", "channel:read:predictions", "channel:read:goals", "channel:read:hype_train",
 			"moderator:read:followers", "moderator:read:shoutouts",
+			"user:read:chat",
+			"channel:moderate", "moderation:read", "channel:read:vips",
+			"channel:manage:raids", "channel:manage:polls",
 		},
 	})
 	h.AuthURL = authURLString
 	lib.LogInfo(authURLString)
 
-	h.LatestFollower = ""
-	h.LatestSubscriber = ""
-
-	h.apiInfoResponseLock = sync.Mutex{}
-	h.apiInfoResponseQueue = make([]interface{}, 0)
-	h.eventProcessLock = sync.Mutex{}
-	h.eventProcessQueue = make([]lib.TwitchMessage, 0)
 	h.twitchClient = client
+	if h.RecordingPath != "" && h.recorder == nil {
+		h.openRecorder()
+	}
+	h.userCache = lib.NewUserCache(client, userCacheTTL, userCacheSize)
+	h.enrichmentLookups = make(chan struct{}, enrichmentMaxLookups)
 
 	h.IsAuthenticated = false
 	// check if we have a access and refresh token to load
@@ -72,40 +120,13 @@ func (h *GodotTwitch) Ready() {
 			return
 		}
 		broadcasterUserID := broadcasterUserResp.Data.Users[0].ID
+		h.broadcasterLock.Lock()
+		h.broadcasterUserID = broadcasterUserID
+		h.broadcasterLock.Unlock()
 
-		followerResp, err := client.GetChannelFollows(&helix.GetChannelFollowsParams{
-			BroadcasterID: broadcasterUserID,
-			First:         1,
-		})
-		if err != nil {
-			fmt.Printf("error: unable to get latest channel follower: %s\n", err.Error())
-		} else {
-			if len(followerResp.Data.Channels) > 0 {
-				follower := followerResp.Data.Channels[0]
-
-				h.apiInfoResponseLock.Lock()
-				h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, LatestFollowerUpdate{follower.Username})
-				h.apiInfoResponseLock.Unlock()
-			}
-		}
-
-		subscribersResp, err := client.GetSubscriptions(&helix.SubscriptionsParams{
-			BroadcasterID: broadcasterUserID,
-			First:         1,
-		})
-		if err != nil {
-			fmt.Printf("error: unable to get latest subscriber: %s\n", err.Error())
-		} else {
-			if len(subscribersResp.Data.Subscriptions) > 0 {
-				subscriber := subscribersResp.Data.Subscriptions[0]
-
-				h.apiInfoResponseLock.Lock()
-				h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, LatestSubscriberUpdate{subscriber.UserName})
-				h.apiInfoResponseLock.Unlock()
-			}
-		}
+		h.bootstrap(client, broadcasterUserID)
 
-		msgChan, sessChan := lib.Websocket(bool(h.UseDebugWS))
+		msgChan, sessChan := lib.Websocket(bool(h.UseDebugWS), h.recorder)
 		for {
 			select {
 			case wsSessionID := <-sessChan:
@@ -117,15 +138,30 @@ func (h *GodotTwitch) Ready() {
 				// every time we (re)connect we have to subscribwe events again
 				lib.EventSetup(client, wsSessionID, broadcasterUserID)
 
+				h.customSubscriptionLock.Lock()
+				h.wsSessionID = wsSessionID
+				customSubscriptions := append([]customSubscription(nil), h.customSubscriptions...)
+				h.customSubscriptionLock.Unlock()
+
+				for _, sub := range customSubscriptions {
+					lib.CustomEventSetup(client, wsSessionID, sub.eventType, sub.version, h.makeCondition(sub.condition))
+				}
+
 			case msg := <-msgChan:
-				h.eventProcessLock.Lock()
-				h.eventProcessQueue = append(h.eventProcessQueue, msg)
-				h.eventProcessLock.Unlock()
+				h.enrichAndQueue(msg, false)
 			}
 		}
 	}()
 }
 
+// currentBroadcasterUserID is empty until the token was validated.
+func (h *GodotTwitch) currentBroadcasterUserID() string {
+	h.broadcasterLock.Lock()
+	defer h.broadcasterLock.Unlock()
+
+	return h.broadcasterUserID
+}
+
 func (h *GodotTwitch) Process(delta Float.X) {
 	if h.hasNewToken {
 		h.hasNewToken = false
@@ -142,6 +178,18 @@ func (h *GodotTwitch) Process(delta Float.X) {
 
 	h.handleApiUpdateTick()
 	h.handleEventTick()
+	h.handleGiftBombTick()
+	h.handleAlertTick(delta)
+	h.handleSessionStatsTick()
+}
+
+// ExitTree writes session stats that were not saved yet and closes the event journal, Ready opens it
+// again.
+func (h *GodotTwitch) ExitTree() {
+	if h.sessionStatsDirty {
+		h.saveSessionStats()
+	}
+	h.closeJournal()
 }
 
 func (h *GodotTwitch) OpenAuthInBrowser() {
@@ -164,6 +212,195 @@ func (h *GodotTwitch) OpenAuthInBrowser() {
 	}
 }
 
+// SubscribeEvent subscribes an additional EventSub type that GodotTwitch does not subscribe by itself.
+// Notifications arrive through "on_event". Condition values of "{broadcaster_user_id}" are replaced with
+// the authenticated broadcaster and an empty condition defaults to the broadcaster_user_id. Conditions
+// with keys the helix client can not send are rejected.
+func (h *GodotTwitch) SubscribeEvent(eventType string, version string, condition map[string]string) {
+	if len(condition) == 0 {
+		condition = map[string]string{"broadcaster_user_id": "{broadcaster_user_id}"}
+	}
+	if unknownKeys := unknownConditionKeys(condition); len(unknownKeys) > 0 {
+		lib.LogErr(fmt.Sprintf(
+			"unable to subscribe to %s: unsupported condition keys %s",
+			eventType,
+			strings.Join(unknownKeys, ", "),
+		))
+		return
+	}
+
+	h.customSubscriptionLock.Lock()
+	defer h.customSubscriptionLock.Unlock()
+
+	sub := customSubscription{eventType: eventType, version: version, condition: condition}
+	h.customSubscriptions = append(h.customSubscriptions, sub)
+
+	// without a session the subscription is created once the websocket connected
+	if h.wsSessionID == "" {
+		return
+	}
+
+	go lib.CustomEventSetup(h.twitchClient, h.wsSessionID, sub.eventType, sub.version, h.makeCondition(sub.condition))
+}
+
+func (h *GodotTwitch) makeCondition(condition map[string]string) helix.EventSubCondition {
+	broadcasterUserID := h.currentBroadcasterUserID()
+	resolvedCondition := make(map[string]string, len(condition))
+	for key, value := range condition {
+		resolvedCondition[key] = strings.ReplaceAll(value, "{broadcaster_user_id}", broadcasterUserID)
+	}
+
+	var eventCondition helix.EventSubCondition
+	conditionJSON, err := json.Marshal(resolvedCondition)
+	if err != nil {
+		lib.LogErr(fmt.Sprintf("unable to read condition: %s", err.Error()))
+		return eventCondition
+	}
+	if err := json.Unmarshal(conditionJSON, &eventCondition); err != nil {
+		lib.LogErr(fmt.Sprintf("unable to read condition: %s", err.Error()))
+	}
+
+	return eventCondition
+}
+
+// unknownConditionKeys returns the sorted keys that helix.EventSubCondition has no field for, they
+// would be dropped silently.
+func unknownConditionKeys(condition map[string]string) []string {
+	knownJSON, err := json.Marshal(helix.EventSubCondition{})
+	if err != nil {
+		return nil
+	}
+	var known map[string]interface{}
+	if err := json.Unmarshal(knownJSON, &known); err != nil {
+		return nil
+	}
+
+	var unknownKeys []string
+	for key := range condition {
+		if _, ok := known[key]; !ok {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	sort.Strings(unknownKeys)
+
+	return unknownKeys
+}
+
+const (
+	RaidActionStart  = "start"
+	RaidActionCancel = "cancel"
+)
+
+// StartRaid starts a raid to the channel with the given login. The raid happens when the broadcaster
+// clicks "Raid Now" or after the 90 second countdown. on_raid_result fires once twitch answered.
+func (h *GodotTwitch) StartRaid(login string) {
+	broadcasterUserID := h.currentBroadcasterUserID()
+	if broadcasterUserID == "" {
+		h.raidFinished(RaidResult{Action: RaidActionStart, Target: login, Error: "not authenticated yet"})
+		return
+	}
+
+	go func() {
+		result := RaidResult{Action: RaidActionStart, Target: login}
+
+		user, found, err := h.userCache.GetUserByLogin(login)
+		switch {
+		case err != nil:
+			result.Error = fmt.Sprintf("unable to fetch user %s: %s", login, err.Error())
+		case !found:
+			result.Error = fmt.Sprintf("unable to fetch user %s: empty result", login)
+		default:
+			raidResp, err := h.twitchClient.StartRaid(&helix.StartRaidParams{
+				FromBroadcasterID: broadcasterUserID,
+				ToBroadcasterID:   user.ID,
+			})
+			if err != nil {
+				result.Error = err.Error()
+			} else if raidResp.Error != "" {
+				result.Error = fmt.Sprintf("%s - %s", raidResp.Error, raidResp.ErrorMessage)
+			}
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, result)
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
+
+// CancelRaid cancels a pending raid. on_raid_result fires once twitch answered.
+func (h *GodotTwitch) CancelRaid() {
+	broadcasterUserID := h.currentBroadcasterUserID()
+	if broadcasterUserID == "" {
+		h.raidFinished(RaidResult{Action: RaidActionCancel, Error: "not authenticated yet"})
+		return
+	}
+
+	go func() {
+		result := RaidResult{Action: RaidActionCancel}
+
+		cancelResp, err := h.twitchClient.CancelRaid(&helix.CancelRaidParams{
+			BroadcasterID: broadcasterUserID,
+		})
+		if err != nil {
+			result.Error = err.Error()
+		} else if cancelResp.Error != "" {
+			result.Error = fmt.Sprintf("%s - %s", cancelResp.Error, cancelResp.ErrorMessage)
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, result)
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
+
+// raidFinished logs the result of start_raid or cancel_raid and emits on_raid_result, it has to be
+// called from the main thread.
+func (h *GodotTwitch) raidFinished(result RaidResult) {
+	switch {
+	case result.Error != "":
+		lib.LogErr(fmt.Sprintf("unable to %s raid: %s", result.Action, result.Error))
+	case result.Action == RaidActionStart:
+		lib.LogInfo(fmt.Sprintf("raid to %s started", result.Target))
+	default:
+		lib.LogInfo("raid canceled")
+	}
+
+	h.OnRaidResult.Emit(result.Action, result.Target, result.Error)
+}
+
+// GetRewards returns all known custom channel point rewards ordered by cost.
+func (h *GodotTwitch) GetRewards() []Reward {
+	rewards := make([]Reward, 0, len(h.rewardCatalog))
+	for _, reward := range h.rewardCatalog {
+		rewards = append(rewards, reward)
+	}
+	sort.Slice(rewards, func(i, j int) bool {
+		if rewards[i].Cost == rewards[j].Cost {
+			return rewards[i].Title < rewards[j].Title
+		}
+		return rewards[i].Cost < rewards[j].Cost
+	})
+
+	return rewards
+}
+
+// preloadEmote starts loading the emote in the configured emote store so it is ready by the time
+// a script asks for it.
+func (h *GodotTwitch) preloadEmote(emoteID string) {
+	if h.EmoteStorePath == "" || emoteID == "" {
+		return
+	}
+
+	storeNode := Node.Instance(h.Super().AsNode().GetNodeOrNull(h.EmoteStorePath))
+	emoteStore, ok := classdb.As[*GodotTwitchEmoteStore](storeNode)
+	if !ok {
+		lib.LogWarn(fmt.Sprintf("%s is not a GodotTwitchEmoteStore", h.EmoteStorePath))
+		return
+	}
+
+	emoteStore.LoadEmote(emoteID)
+}
+
 func (h *GodotTwitch) readTokens() bool {
 	if !FileAccess.FileExists("user://twitch_access_token.txt") {
 		return false
@@ -186,3 +423,42 @@ func (h *GodotTwitch) readTokens() bool {
 	h.twitchClient.SetRefreshToken(gdRefresh)
 	return true
 }
+
+// GetUserInfo looks up a user by login or ID and emits on_user_info with the result. Numeric values
+// are tried as ID first and as login if no user has that ID.
+func (h *GodotTwitch) GetUserInfo(loginOrID string) {
+	if h.userCache == nil {
+		lib.LogErr("unable to get user info: client not ready")
+		return
+	}
+
+	go func() {
+		var user helix.User
+		var found bool
+
+		if isNumeric(loginOrID) {
+			users, err := h.userCache.GetUsersByID([]string{loginOrID})
+			if err != nil {
+				lib.LogErr(fmt.Sprintf("unable to fetch user %s: %s", loginOrID, err.Error()))
+			}
+			user, found = users[loginOrID]
+		}
+
+		if !found {
+			var err error
+			user, found, err = h.userCache.GetUserByLogin(loginOrID)
+			if err != nil {
+				lib.LogErr(fmt.Sprintf("unable to fetch user %s: %s", loginOrID, err.Error()))
+			}
+		}
+
+		var info UserInfo
+		if found {
+			info = userInfoFromHelix(user)
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, UserInfoResponse{Query: loginOrID, Info: info})
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
@@ -0,0 +1,76 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+
+	"graphics.gd/classdb/ProjectSettings"
+	"graphics.gd/variant/Float"
+)
+
+func (h *GodotTwitch) openRecorder() {
+	recorder, err := lib.NewRecorder(ProjectSettings.GlobalizePath(h.RecordingPath))
+	if err != nil {
+		lib.LogErr(fmt.Sprintf("unable to open recording: %s", err.Error()))
+		return
+	}
+	h.recorder = recorder
+}
+
+// StartPlayback feeds a file written with recording_path into this node like a live connection.
+// A speed of 1 keeps the original timing, 2 plays twice as fast and 0 waits for step_playback before
+// every frame. Replayed events are not written to the event journal.
+func (h *GodotTwitch) StartPlayback(path string, speed Float.X) {
+	frames, err := lib.ReadRecording(ProjectSettings.GlobalizePath(path))
+	if err != nil {
+		lib.LogErr(fmt.Sprintf("unable to start playback: %s", err.Error()))
+		return
+	}
+
+	h.StopPlayback()
+	player := lib.NewPlayer(frames, float64(speed))
+	h.player = player
+
+	go func() {
+		err := player.Play(func(frame lib.RecordedFrame) error {
+			msg, err := lib.DecodeFrame(frame)
+			if err != nil {
+				lib.LogWarn(err.Error())
+				return nil
+			}
+
+			// session messages belong to the recorded connection
+			if msg.Metadata.Type != "notification" {
+				return nil
+			}
+
+			h.enrichAndQueue(msg, true)
+			return nil
+		})
+		if err != nil {
+			lib.LogErr(fmt.Sprintf("playback stopped: %s", err.Error()))
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, PlaybackFinished{Path: path})
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
+
+// StepPlayback sends the next frame of the running playback right away.
+func (h *GodotTwitch) StepPlayback() {
+	if h.player == nil {
+		return
+	}
+
+	h.player.Step()
+}
+
+func (h *GodotTwitch) StopPlayback() {
+	if h.player == nil {
+		return
+	}
+
+	h.player.Stop()
+	h.player = nil
+}
@@ -0,0 +1,158 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+const (
+	PollActionCreate = "create"
+	PollActionEnd    = "end"
+)
+
+// pollsPageSize is the largest page the get polls api returns.
+const pollsPageSize = "20"
+
+// CreatePoll starts a poll that runs for duration seconds. Viewers may buy additional votes for
+// channel_points_per_vote channel points, zero disables that. Once the poll started on_poll_begin
+// fires and the result arrives through on_poll_end, on_poll_error fires if it could not be created.
+func (h *GodotTwitch) CreatePoll(title string, choices []string, duration int, channelPointsPerVote int) {
+	broadcasterUserID := h.currentBroadcasterUserID()
+	if broadcasterUserID == "" {
+		h.pollFailed(PollActionCreate, "not authenticated yet")
+		return
+	}
+	if err := lib.ValidatePoll(title, choices, duration, channelPointsPerVote); err != nil {
+		h.pollFailed(PollActionCreate, err.Error())
+		return
+	}
+
+	params := &helix.CreatePollParams{
+		BroadcasterID:              broadcasterUserID,
+		Title:                      title,
+		Duration:                   duration,
+		ChannelPointsVotingEnabled: channelPointsPerVote > 0,
+		ChannelPointsPerVote:       channelPointsPerVote,
+	}
+	for _, choice := range choices {
+		params.Choices = append(params.Choices, helix.PollChoiceParam{Title: choice})
+	}
+
+	client := h.twitchClient
+	go func() {
+		pollResp, err := client.CreatePoll(params)
+		if err != nil {
+			h.queuePollError(PollActionCreate, fmt.Sprintf("%s: %s", title, err.Error()))
+			return
+		}
+		if err := helixError(pollResp.ResponseCommon); err != nil {
+			h.queuePollError(PollActionCreate, fmt.Sprintf("%s: %s", title, err.Error()))
+			return
+		}
+
+		lib.LogInfo(fmt.Sprintf("poll %s created", title))
+	}()
+}
+
+// EndPoll ends the poll with the given ID, an empty ID ends the active poll. Archived polls are
+// hidden from chat right away, otherwise the result stays visible for a while. on_poll_error fires
+// if it could not be ended.
+func (h *GodotTwitch) EndPoll(id string, archive bool) {
+	broadcasterUserID := h.currentBroadcasterUserID()
+	if broadcasterUserID == "" {
+		h.pollFailed(PollActionEnd, "not authenticated yet")
+		return
+	}
+
+	if id == "" {
+		if !h.ActivePoll.IsActive {
+			h.pollFailed(PollActionEnd, "no active poll")
+			return
+		}
+		id = h.ActivePoll.ID
+	}
+
+	status := "TERMINATED"
+	if archive {
+		status = "ARCHIVED"
+	}
+
+	client := h.twitchClient
+	go func() {
+		pollResp, err := client.EndPoll(&helix.EndPollParams{
+			BroadcasterID: broadcasterUserID,
+			ID:            id,
+			Status:        status,
+		})
+		if err != nil {
+			h.queuePollError(PollActionEnd, fmt.Sprintf("%s: %s", id, err.Error()))
+			return
+		}
+		if err := helixError(pollResp.ResponseCommon); err != nil {
+			h.queuePollError(PollActionEnd, fmt.Sprintf("%s: %s", id, err.Error()))
+			return
+		}
+
+		lib.LogInfo(fmt.Sprintf("poll %s ended", id))
+	}()
+}
+
+// GetPolls fetches the polls of the last 90 days, newest first, and emits on_polls with them.
+func (h *GodotTwitch) GetPolls() {
+	broadcasterUserID := h.currentBroadcasterUserID()
+	if broadcasterUserID == "" {
+		lib.LogErr("unable to get polls: not authenticated yet")
+		return
+	}
+
+	client := h.twitchClient
+	go func() {
+		update := PollsResponse{}
+
+		// twitch keeps polls for 90 days, the pages cover all of them
+		var cursor string
+		for {
+			pollsResp, err := client.GetPolls(&helix.PollsParams{
+				BroadcasterID: broadcasterUserID,
+				After:         cursor,
+				First:         pollsPageSize,
+			})
+			if err != nil {
+				lib.LogErr(fmt.Sprintf("unable to get polls: %s", err.Error()))
+				return
+			}
+			if err := helixError(pollsResp.ResponseCommon); err != nil {
+				lib.LogErr(fmt.Sprintf("unable to get polls: %s", err.Error()))
+				return
+			}
+
+			for _, poll := range pollsResp.Data.Polls {
+				update.Polls = append(update.Polls, pollFromHelix(poll))
+			}
+
+			cursor = pollsResp.Data.Pagination.Cursor
+			if cursor == "" || len(pollsResp.Data.Polls) <= 0 {
+				break
+			}
+		}
+
+		h.apiInfoResponseLock.Lock()
+		h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, update)
+		h.apiInfoResponseLock.Unlock()
+	}()
+}
+
+// pollFailed logs the error and emits on_poll_error, it has to be called from the main thread.
+func (h *GodotTwitch) pollFailed(action string, message string) {
+	lib.LogErr(fmt.Sprintf("unable to %s poll: %s", action, message))
+	h.OnPollError.Emit(action, message)
+}
+
+// queuePollError hands an api error of a poll request over to the main thread.
+func (h *GodotTwitch) queuePollError(action string, message string) {
+	h.apiInfoResponseLock.Lock()
+	h.apiInfoResponseQueue = append(h.apiInfoResponseQueue, PollError{Action: action, Message: message})
+	h.apiInfoResponseLock.Unlock()
+}
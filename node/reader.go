@@ -3,212 +3,197 @@ package node
 import (
 	"fmt"
 	"main/lib"
+	"math"
 	"strconv"
-)
+	"time"
 
-func (h *GodotTwitch) readStringFromEvent(eventPayload map[string]interface{}, key string) string {
-	val, ok := eventPayload[key]
-	if !ok {
-		lib.LogErr(fmt.Sprintf("missing event data: %s", key))
-		return ""
-	}
+	"github.com/nicklaw5/helix/v2"
+	"graphics.gd/variant/Float"
+)
 
+// toGodotValue prepares decoded JSON to be passed to godot. Whole numbers are turned into ints as
+// JSON decoding reads every number as float64.
+func toGodotValue(val interface{}) interface{} {
 	switch v := val.(type) {
-	case string:
-		return v
-	case int:
-		return fmt.Sprintf("%d", v)
-	case int32:
-		return fmt.Sprintf("%d", v)
-	case int64:
-		return fmt.Sprintf("%d", v)
-	case uint:
-		return fmt.Sprintf("%d", v)
-	case uint32:
-		return fmt.Sprintf("%d", v)
-	case uint64:
-		return fmt.Sprintf("%d", v)
-	case float32:
-		return fmt.Sprintf("%.2f", v)
+	case map[string]interface{}:
+		dict := make(map[string]interface{}, len(v))
+		for key, entry := range v {
+			dict[key] = toGodotValue(entry)
+		}
+		return dict
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, entry := range v {
+			array[i] = toGodotValue(entry)
+		}
+		return array
 	case float64:
-		return fmt.Sprintf("%.2f", v)
-	case nil:
-		return ""
+		if v == math.Trunc(v) && math.Abs(v) < math.MaxInt64 {
+			return int(v)
+		}
+		return v
 	default:
-		lib.LogWarn(fmt.Sprintf("unknown event data type %T", val))
-		return ""
+		return v
 	}
 }
 
-func (h *GodotTwitch) readIntFromEvent(eventPayload map[string]interface{}, key string) int {
-	val, ok := eventPayload[key]
-	if !ok {
-		lib.LogErr(fmt.Sprintf("missing event data: %s", key))
+// unixTime returns zero for timestamps twitch sent as null.
+func unixTime(t time.Time) int {
+	if t.IsZero() {
 		return 0
 	}
 
-	switch v := val.(type) {
-	case string:
-		asint, err := strconv.Atoi(v)
-		if err != nil {
-			lib.LogErr(fmt.Sprintf("unable to read %s as int: %s", key, err.Error()))
-			return 0
-		}
-		return asint
-	case int:
-		return int(v)
-	case int32:
-		return int(v)
-	case int64:
-		return int(v)
-	case uint:
-		return int(v)
-	case uint32:
-		return int(v)
-	case uint64:
-		return int(v)
-	case float32:
-		return int(v)
-	case float64:
-		return int(v)
-	case nil:
-		return int(0)
-	default:
-		lib.LogWarn(fmt.Sprintf("unknown event data type %T", val))
-		return int(0)
-	}
+	return int(t.Unix())
 }
 
-func (h *GodotTwitch) readBoolFromEvent(eventPayload map[string]interface{}, key string) bool {
-	val, ok := eventPayload[key]
-	if !ok {
-		lib.LogErr(fmt.Sprintf("missing event data: %s", key))
-		return false
+// parseTier reads sub tiers which twitch sends as strings like "1000".
+func parseTier(tier string) (int, error) {
+	if tier == "" {
+		return 0, nil
 	}
 
-	valAsBoo, isBool := val.(bool)
-	if !isBool {
-		lib.LogWarn(fmt.Sprintf("cannot read %T as bool", val))
-		return false
+	asint, err := strconv.Atoi(tier)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read tier %s as int: %w", tier, err)
 	}
 
-	return valAsBoo
+	return asint, nil
 }
 
-func (h *GodotTwitch) readPollChoices(
-	eventMsg lib.TwitchMessage,
-	onlyBeginning bool,
-) []Choice {
-	var choicesArray []Choice
-	choicesInterfaces, ok := eventMsg.Payload.Event["choices"]
-	if !ok {
-		lib.LogErr("poll without any choices")
-		return nil
+func minorUnitsToFloat(value int, decimalPlaces int) Float.X {
+	if decimalPlaces <= 0 {
+		return Float.X(value)
 	}
-	choices := choicesInterfaces.([]interface{})
-	if len(choices) > 0 {
-		for _, choiceInterface := range choices {
-			choice, ok := choiceInterface.(map[string]interface{})
-			if !ok {
-				lib.LogErr(fmt.Sprintf("error converting single choice: expected map[string]interface{} but got %T", choiceInterface))
-				return nil
-			}
 
-			choiceID := h.readStringFromEvent(choice, "id")
-			choiceName := h.readStringFromEvent(choice, "title")
+	return Float.X(value) / Float.Pow(10, Float.X(decimalPlaces))
+}
 
-			var choiceDict Choice
-			choiceDict.ID = choiceID
-			choiceDict.Title = choiceName
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
 
-			if !onlyBeginning {
-				bitsVoted := h.readIntFromEvent(choice, "bits_votes")
-				pointsVoted := h.readIntFromEvent(choice, "channel_points_votes")
-				totalVoted := h.readIntFromEvent(choice, "votes")
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
 
-				choiceDict.BitsVoted = bitsVoted
-				choiceDict.ChannelPointsVoted = pointsVoted
-				choiceDict.Votes = totalVoted
-			}
+	return true
+}
 
-			choicesArray = append(choicesArray, choiceDict)
+func userInfoFromHelix(user helix.User) UserInfo {
+	var info UserInfo
+	info.ID = user.ID
+	info.Login = user.Login
+	info.DisplayName = user.DisplayName
+	info.ProfilePictureURL = user.ProfileImageURL
+	info.BroadcasterType = user.BroadcasterType
+	info.Description = user.Description
+	info.UnixCreatedAt = unixTime(user.CreatedAt.Time)
+
+	return info
+}
+
+func redemptionFromEvent(event lib.ChannelPointsRedemptionEventV1) Redemption {
+	var redemption Redemption
+	redemption.ID = event.ID
+	redemption.UserID = event.UserID
+	redemption.UserLogin = event.UserLogin
+	redemption.UserName = event.UserName
+	redemption.UserInput = event.UserInput
+	redemption.Status = event.Status
+	redemption.UnixRedeemedAt = unixTime(event.RedeemedAt)
+	redemption.RewardID = event.Reward.ID
+	redemption.RewardTitle = event.Reward.Title
+	redemption.RewardPrompt = event.Reward.Prompt
+	redemption.RewardCost = event.Reward.Cost
+
+	return redemption
+}
+
+func rewardFromEvent(event lib.ChannelPointsRewardEventV1) Reward {
+	var reward Reward
+	reward.ID = event.ID
+	reward.Title = event.Title
+	reward.Prompt = event.Prompt
+	reward.Cost = event.Cost
+	reward.BackgroundColor = event.BackgroundColor
+	reward.IsEnabled = event.IsEnabled
+	reward.IsPaused = event.IsPaused
+	reward.IsInStock = event.IsInStock
+	reward.IsUserInputRequired = event.IsUserInputRequired
+
+	return reward
+}
+
+func charityCampaignFromEvent(event lib.CharityCampaignEventV1) CharityCampaign {
+	var campaign CharityCampaign
+	campaign.ID = event.ID
+	campaign.CharityName = event.CharityName
+	campaign.CharityDescription = event.CharityDescription
+	campaign.CharityLogoURL = event.CharityLogo
+	campaign.CharityWebsite = event.CharityWebsite
+	campaign.CurrentValue = event.CurrentAmount.Value
+	campaign.TargetValue = event.TargetAmount.Value
+	campaign.DecimalPlaces = event.CurrentAmount.DecimalPlaces
+	campaign.Currency = event.CurrentAmount.Currency
+	campaign.CurrentAmount = minorUnitsToFloat(event.CurrentAmount.Value, event.CurrentAmount.DecimalPlaces)
+	campaign.TargetAmount = minorUnitsToFloat(event.TargetAmount.Value, event.TargetAmount.DecimalPlaces)
+
+	return campaign
+}
+
+func pollChoicesFromEvent(event lib.ChannelPollEventV1, onlyBeginning bool) []Choice {
+	var choicesArray []Choice
+	for _, choice := range event.Choices {
+		var choiceDict Choice
+		choiceDict.ID = choice.ID
+		choiceDict.Title = choice.Title
+
+		if !onlyBeginning {
+			choiceDict.BitsVoted = choice.BitsVotes
+			choiceDict.ChannelPointsVoted = choice.ChannelPointsVotes
+			choiceDict.Votes = choice.Votes
 		}
+
+		choicesArray = append(choicesArray, choiceDict)
 	}
 
 	return choicesArray
 }
 
-func (h *GodotTwitch) readPredictionOutComes(
-	eventMsg lib.TwitchMessage,
+func predictionOutcomesFromEvent(
+	event lib.ChannelPredictionEventV1,
 	onlyBeginning bool, withWinnings bool,
 ) []PredictionOutcome {
 	var outcomesArray []PredictionOutcome
-	outcomesInterfaces, ok := eventMsg.Payload.Event["outcomes"]
-	if !ok {
-		lib.LogErr("prediction without any outcomes")
-		return nil
-	}
-	outcomes := outcomesInterfaces.([]interface{})
-	if len(outcomes) > 0 {
-		for _, outcomeInterface := range outcomes {
-			outcome, ok := outcomeInterface.(map[string]interface{})
-			if !ok {
-				lib.LogErr(fmt.Sprintf("error converting single outcome: expected map[string]interface{} but got %T", outcomeInterface))
-				return nil
-			}
-
-			outcomeID := h.readStringFromEvent(outcome, "id")
-			outcomeName := h.readStringFromEvent(outcome, "title")
-			outcomeColor := h.readStringFromEvent(outcome, "color")
-
-			var outcomeDict PredictionOutcome
-			outcomeDict.ID = outcomeID
-			outcomeDict.Title = outcomeName
-			outcomeDict.Color = outcomeColor
-
-			if !onlyBeginning {
-				outcomeUsers := h.readIntFromEvent(outcome, "users")
-				outcomePoints := h.readIntFromEvent(outcome, "channel_points")
-
-				var topPredictorsArray []TopPredictor
-				topPredictorsInterface, ok := eventMsg.Payload.Event["top_predictors"]
-				if !ok {
-					lib.LogErr("prediction without any top_predictors")
-				} else {
-					topPredictors := topPredictorsInterface.([]interface{})
-					if len(topPredictors) > 0 {
-						for _, topPredictorInterface := range topPredictors {
-							topPredictor, ok := topPredictorInterface.(map[string]interface{})
-							if !ok {
-								lib.LogErr(fmt.Sprintf("error converting single top predictor: expected map[string]interface{} but got %T", topPredictorInterface))
-								return nil
-							}
-
-							tpID := h.readStringFromEvent(topPredictor, "user_id")
-							tpName := h.readStringFromEvent(topPredictor, "user_name")
-							tpPoints := h.readIntFromEvent(topPredictor, "channel_points_used")
-
-							var topPredictorDict TopPredictor
-							topPredictorDict.UserID = tpID
-							topPredictorDict.UserName = tpName
-							topPredictorDict.ChannelPointsUsed = tpPoints
-							if withWinnings {
-								tpWon := h.readIntFromEvent(topPredictor, "channel_points_won")
-								topPredictorDict.ChannelPointsWon = tpWon
-							}
-
-							topPredictorsArray = append(topPredictorsArray, topPredictorDict)
-						}
-					}
+	for _, outcome := range event.Outcomes {
+		var outcomeDict PredictionOutcome
+		outcomeDict.ID = outcome.ID
+		outcomeDict.Title = outcome.Title
+		outcomeDict.Color = outcome.Color
+
+		if !onlyBeginning {
+			var topPredictorsArray []TopPredictor
+			for _, topPredictor := range outcome.TopPredictors {
+				var topPredictorDict TopPredictor
+				topPredictorDict.UserID = topPredictor.UserID
+				topPredictorDict.UserName = topPredictor.UserName
+				topPredictorDict.ChannelPointsUsed = topPredictor.ChannelPointsUsed
+				if withWinnings {
+					topPredictorDict.ChannelPointsWon = topPredictor.ChannelPointsWon
 				}
 
-				outcomeDict.Users = outcomeUsers
-				outcomeDict.ChannelPoints = outcomePoints
-				outcomeDict.TopPredictors = topPredictorsArray
+				topPredictorsArray = append(topPredictorsArray, topPredictorDict)
 			}
 
-			outcomesArray = append(outcomesArray, outcomeDict)
+			outcomeDict.Users = outcome.Users
+			outcomeDict.ChannelPoints = outcome.ChannelPoints
+			outcomeDict.TopPredictors = topPredictorsArray
 		}
+
+		outcomesArray = append(outcomesArray, outcomeDict)
 	}
 
 	return outcomesArray
@@ -0,0 +1,153 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+	"time"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Resource"
+	"graphics.gd/variant/Float"
+)
+
+type TwitchRule struct {
+	classdb.Extension[TwitchRule, Resource.Instance] `gd:"TwitchRule"
+		Fires on_rule_triggered on GodotTwitch when an event of event_type matches all conditions`
+
+	RuleName  string `gd:"rule_name"`
+	EventType string `gd:"event_type"
+		EventSub type like channel.channel_points_custom_reward_redemption.add`
+	Action string `gd:"action"
+		Optional action name passed to on_rule_action`
+	Conditions []Resource.Instance `gd:"conditions"
+		TwitchRuleCondition resources, all of them have to match`
+	CooldownSeconds Float.X `gd:"cooldown_seconds"
+		Seconds after triggering in which the rule does not trigger again`
+	PerUserLimit int `gd:"per_user_limit"
+		Max triggers per user within per_user_window_seconds, zero means no limit`
+	PerUserWindowSeconds Float.X `gd:"per_user_window_seconds"
+		Window for per_user_limit, zero means the whole session`
+}
+
+type TwitchRuleCondition struct {
+	classdb.Extension[TwitchRuleCondition, Resource.Instance] `gd:"TwitchRuleCondition"
+		Condition on a field of the event payload as sent by twitch`
+
+	Field string `gd:"field"
+		Path into the event payload, nested fields are separated by dots like reward.title`
+	Operator string `gd:"operator"
+		One of equals, not_equals, contains, regex, range, at_least or at_most. equals and contains ignore case`
+	Value string `gd:"value"
+		Compared value for equals, not_equals, contains and the pattern for regex`
+	Min Float.X `gd:"min"
+		Lower bound for range and at_least`
+	Max Float.X `gd:"max"
+		Upper bound for range and at_most`
+}
+
+type ruleState struct {
+	lastTriggered time.Time
+	userTriggers  map[string][]time.Time
+}
+
+// evaluateRules checks the rules against the payload of every notification.
+func (h *GodotTwitch) evaluateRules(eventType string, event map[string]interface{}) {
+	now := time.Now()
+
+	for _, ruleResource := range h.Rules {
+		rule, ok := classdb.As[*TwitchRule](ruleResource)
+		if !ok || rule.EventType != eventType {
+			continue
+		}
+
+		matches, err := h.ruleMatches(rule, event)
+		if err != nil {
+			lib.LogErr(fmt.Sprintf("unable to evaluate rule %s: %s", rule.RuleName, err.Error()))
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		// keyed by the resource, rule names may be empty or shared
+		state, ok := h.ruleStates[rule]
+		if !ok {
+			state = &ruleState{userTriggers: make(map[string][]time.Time)}
+			h.ruleStates[rule] = state
+		}
+
+		if rule.CooldownSeconds > 0 && now.Sub(state.lastTriggered) < secondsToDuration(rule.CooldownSeconds) {
+			continue
+		}
+
+		userID := ruleUserID(event)
+		if rule.PerUserLimit > 0 && userID != "" {
+			triggers := state.userTriggers[userID]
+			if rule.PerUserWindowSeconds > 0 {
+				windowStart := now.Add(-secondsToDuration(rule.PerUserWindowSeconds))
+				var inWindow []time.Time
+				for _, triggeredAt := range triggers {
+					if triggeredAt.After(windowStart) {
+						inWindow = append(inWindow, triggeredAt)
+					}
+				}
+				triggers = inWindow
+			}
+
+			if len(triggers) >= rule.PerUserLimit {
+				state.userTriggers[userID] = triggers
+				continue
+			}
+			state.userTriggers[userID] = append(triggers, now)
+		}
+
+		state.lastTriggered = now
+
+		h.OnRuleTriggered.Emit(rule.RuleName, event)
+		if rule.Action != "" {
+			h.OnRuleAction.Emit(rule.Action, rule.RuleName, event)
+		}
+	}
+}
+
+func (h *GodotTwitch) ruleMatches(rule *TwitchRule, event map[string]interface{}) (bool, error) {
+	for _, conditionResource := range rule.Conditions {
+		condition, ok := classdb.As[*TwitchRuleCondition](conditionResource)
+		if !ok {
+			return false, fmt.Errorf("condition is not a TwitchRuleCondition")
+		}
+
+		value, found := lib.LookupField(event, condition.Field)
+		if !found {
+			return false, nil
+		}
+
+		matches, err := lib.MatchRuleCondition(lib.RuleCondition{
+			Field:    condition.Field,
+			Operator: condition.Operator,
+			Value:    condition.Value,
+			Min:      float64(condition.Min),
+			Max:      float64(condition.Max),
+		}, value, h.rulePatterns)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// ruleUserID returns the user that caused the event for per user limits.
+func ruleUserID(event map[string]interface{}) string {
+	for _, key := range []string{"user_id", "chatter_user_id", "from_broadcaster_user_id"} {
+		if userID, ok := event[key].(string); ok && userID != "" {
+			return userID
+		}
+	}
+
+	return ""
+}
+
+func secondsToDuration(seconds Float.X) time.Duration {
+	return time.Duration(float64(seconds) * float64(time.Second))
+}
@@ -0,0 +1,140 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+	"time"
+
+	"graphics.gd/classdb/ProjectSettings"
+)
+
+const sessionStatsPath = "user://twitch_session_stats.json"
+
+// sessionStatsSaveInterval limits how often the stats are written while events keep coming in.
+const sessionStatsSaveInterval = 5 * time.Second
+
+type SessionStats struct {
+	UnixStartedAt       int                    `gd:"unix_started_at"`
+	UnixStreamStartedAt int                    `gd:"unix_stream_started_at"`
+	Followers           []string               `gd:"followers"`
+	SubsByTier          map[string]interface{} `gd:"subs_by_tier"`
+	GiftedSubsByGifter  map[string]interface{} `gd:"gifted_subs_by_gifter"`
+	BitsByUser          map[string]interface{} `gd:"bits_by_user"`
+	Raids               []RaidStat             `gd:"raids"`
+	RedemptionsByReward map[string]interface{} `gd:"redemptions_by_reward"`
+	DonationsByCurrency map[string]interface{} `gd:"donations_by_currency"`
+}
+
+type RaidStat struct {
+	FromUsername   string `gd:"from_username"`
+	Viewers        int    `gd:"viewers"`
+	UnixReceivedAt int    `gd:"unix_received_at"`
+}
+
+// loadSessionStats continues the stats of the last session, e.g. after a crash mid stream.
+func (h *GodotTwitch) loadSessionStats() {
+	stats, err := lib.LoadSessionStats(ProjectSettings.GlobalizePath(sessionStatsPath))
+	if err != nil {
+		lib.LogErr(fmt.Sprintf("unable to load session stats: %s", err.Error()))
+		stats = lib.NewSessionStats(time.Now())
+	}
+	h.sessionStats = stats
+}
+
+func (h *GodotTwitch) recordSessionStats(eventType string, decodedEvent interface{}) {
+	if h.sessionStats == nil {
+		return
+	}
+
+	if online, ok := decodedEvent.(lib.StreamOnlineEventV1); ok {
+		h.startSessionStats(online.StartedAt, unixTime(online.StartedAt))
+		return
+	}
+
+	if h.sessionStats.Record(eventType, decodedEvent, h.currentBroadcasterUserID()) {
+		h.sessionStatsDirty = true
+	}
+}
+
+// checkSessionStatsStream starts new stats if the loaded stats belong to another stream than the
+// one that is live.
+func (h *GodotTwitch) checkSessionStatsStream() {
+	if h.sessionStats == nil || !h.IsLive || h.sessionStats.StreamStartedAt == int64(h.StreamStartedAt) {
+		return
+	}
+
+	lib.LogInfo("session stats belong to another stream. start new session stats")
+	h.startSessionStats(time.Unix(int64(h.StreamStartedAt), 0), h.StreamStartedAt)
+}
+
+func (h *GodotTwitch) startSessionStats(startedAt time.Time, streamStartedAt int) {
+	h.sessionStats = lib.NewSessionStats(startedAt)
+	h.sessionStats.StreamStartedAt = int64(streamStartedAt)
+	h.sessionStatsDirty = true
+}
+
+func (h *GodotTwitch) handleSessionStatsTick() {
+	if !h.sessionStatsDirty || time.Since(h.sessionStatsSavedAt) < sessionStatsSaveInterval {
+		return
+	}
+
+	h.saveSessionStats()
+}
+
+func (h *GodotTwitch) saveSessionStats() {
+	if h.sessionStats == nil {
+		return
+	}
+
+	h.sessionStatsDirty = false
+	h.sessionStatsSavedAt = time.Now()
+	if err := h.sessionStats.Save(ProjectSettings.GlobalizePath(sessionStatsPath)); err != nil {
+		lib.LogErr(fmt.Sprintf("unable to save session stats: %s", err.Error()))
+	}
+}
+
+// GetSessionStats returns the stats of the current stream, they are reset on stream.online and on
+// startup if another stream is live. Names of anonymous gifters and cheerers are empty, tiers are
+// 1000, 2000 or 3000.
+func (h *GodotTwitch) GetSessionStats() SessionStats {
+	if h.sessionStats == nil {
+		return SessionStats{}
+	}
+
+	stats := SessionStats{
+		UnixStartedAt:       int(h.sessionStats.StartedAt),
+		UnixStreamStartedAt: int(h.sessionStats.StreamStartedAt),
+		Followers:           append([]string(nil), h.sessionStats.Followers...),
+		SubsByTier:          countsToDict(h.sessionStats.SubsByTier),
+		GiftedSubsByGifter:  countsToDict(h.sessionStats.GiftedSubsByGifter),
+		BitsByUser:          countsToDict(h.sessionStats.BitsByUser),
+		RedemptionsByReward: countsToDict(h.sessionStats.RedemptionsByReward),
+		DonationsByCurrency: make(map[string]interface{}),
+	}
+	for _, raid := range h.sessionStats.Raids {
+		stats.Raids = append(stats.Raids, RaidStat{
+			FromUsername:   raid.FromUserName,
+			Viewers:        raid.Viewers,
+			UnixReceivedAt: int(raid.ReceivedAt),
+		})
+	}
+	for currency, total := range h.sessionStats.DonationsByCurrency {
+		stats.DonationsByCurrency[currency] = minorUnitsToFloat(total.Value, total.DecimalPlaces)
+	}
+
+	return stats
+}
+
+// ResetSessionStats starts new stats like stream.online does.
+func (h *GodotTwitch) ResetSessionStats() {
+	h.startSessionStats(time.Now(), h.StreamStartedAt)
+}
+
+func countsToDict(counts map[string]int) map[string]interface{} {
+	dict := make(map[string]interface{}, len(counts))
+	for key, count := range counts {
+		dict[key] = count
+	}
+
+	return dict
+}
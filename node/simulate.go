@@ -0,0 +1,35 @@
+package node
+
+import (
+	"fmt"
+	"main/lib"
+)
+
+// simulatedEnrichment replaces the helix lookups for simulated events.
+var simulatedEnrichment = eventEnrichment{
+	profilePictureURL: "https://static-cdn.jtvnw.net/user-default-pictures-uv/cdd517fe-def4-11e9-948e-784f43822e80-profile_image-300x300.png",
+	lastStreamGame:    "Just Chatting",
+	lastStreamTitle:   "Simulated stream",
+}
+
+// SimulateEvent emits a made up event of the given EventSub type through the same path as real
+// events, without any network access. Fields in overrides replace the sample payload, nested
+// dictionaries are merged. Simulated events are not written to the event journal.
+func (h *GodotTwitch) SimulateEvent(eventType string, overrides map[string]interface{}) {
+	broadcaster := lib.BroadcasterRef{
+		BroadcasterUserID:    h.currentBroadcasterUserID(),
+		BroadcasterUserLogin: "simulated_broadcaster",
+		BroadcasterUserName:  "Simulated_Broadcaster",
+	}
+	if broadcaster.BroadcasterUserID == "" {
+		broadcaster.BroadcasterUserID = "1337"
+	}
+
+	msg, err := lib.SimulatedMessage(eventType, broadcaster, overrides)
+	if err != nil {
+		lib.LogErr(fmt.Sprintf("unable to simulate event: %s", err.Error()))
+		return
+	}
+
+	h.queueEvent(&enrichedMessage{msg: msg, enrichment: simulatedEnrichment, simulated: true})
+}
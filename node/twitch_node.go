@@ -2,12 +2,17 @@ package node
 
 import (
 	"main/lib"
+	"regexp"
 	"sync"
+	"time"
 
 	"github.com/nicklaw5/helix/v2"
 	"graphics.gd/classdb"
 	"graphics.gd/classdb/Node"
+	"graphics.gd/classdb/Resource"
 	"graphics.gd/variant/Float"
+	"graphics.gd/variant/NodePath"
+	"graphics.gd/variant/Object"
 	"graphics.gd/variant/Signal"
 )
 
@@ -26,32 +31,103 @@ type GodotTwitch struct {
 	IsAuthenticated bool `gd:"is_authed"
 		True if client has been authenticated. This can be true when _ready if store_token is true and valid tokens are stored on disk`
 
+	EmoteStorePath NodePath.String `gd:"emote_store_path"
+		Optional GodotTwitchEmoteStore that gets emotes from events loaded ahead of time`
+
+	OnEvent Signal.Quad[string, string, map[string]interface{}, map[string]interface{}] `gd:"on_event(type,version,event,metadata)"
+		Fires for every received notification, including event types without a dedicated signal`
+
+	OnEventError Signal.Pair[string, string] `gd:"on_event_error(type,message)"
+		Fires when a notification could not be decoded or handled`
+
 	OnFollow Signal.Solo[string] `gd:"on_follow(username)"
 		channel.follow`
 	LatestFollower string `gd:"latest_follower"
-		Username of latest follower`
+		Username of latest follower, restored from the event journal on startup`
 
 	OnSubscibtion Signal.Trio[string, int, int] `gd:"on_subscribtion(username,months,tier)"
 		Twitch Event: channel.subscribe ( only for non gifts ) and channel.subscription.message`
 	OnGiftSubs Signal.Quad[string, int, int, int] `gd:"on_sub_gift(username,qty,tier,total)"
 		Twitch Event: channel.subscription.gift, if Gifter is anonymous username may be empty and total may be zero`
+	OnGiftBombComplete Signal.Trio[string, int, []string] `gd:"on_gift_bomb_complete(gifter,tier,recipients)"
+		Fires once the recipients of channel.subscription.gift arrived or after 10 seconds with the recipients known by then, gifter is empty for anonymous gifts`
 	LatestSubscriber string `gd:"latest_subscriber"
-		Username of latest subscriber`
+		Username of latest subscriber, restored from the event journal on startup`
+
+	OnCheer Signal.Trio[string, int, string] `gd:"on_cheer(username,bits,message)"
+		Twitch Event: channel.cheer, username is empty for anonymous cheers`
+
+	OnBitsLeaderboard Signal.Pair[string, []BitsLeaderboardEntry] `gd:"on_bits_leaderboard(period,entries)"
+		Result of get_bits_leaderboard, entries are ordered by rank`
+	RefreshBitsLeaderboardOnCheer bool `gd:"refresh_bits_leaderboard_on_cheer"
+		If true the last get_bits_leaderboard call is repeated on every channel.cheer`
 
 	OnIncomingRaid Signal.Trio[string, string, int] `gd:"on_raid(username,profile_picture_url,viewer_count)"
-		Twitch Event: channel.raid ( incoming raids )`
+		Twitch Event: channel.raid ( incoming raids ), profile_picture_url is empty if it could not be fetched`
+	OnOutgoingRaid Signal.Pair[string, int] `gd:"on_outgoing_raid(target,viewers)"
+		Twitch Event: channel.raid ( outgoing raids )`
+	OnRaidResult Signal.Trio[string, string, string] `gd:"on_raid_result(action,target,error)"
+		Result of start_raid or cancel_raid, action is start or cancel and error is empty on success. target is empty for cancel`
 	OnRewardRedemtionAdd Signal.Hexa[string, string, string, string, string, int] `gd:"on_redeem(username,user_input,reward_id,reward_title,reward_prompt,reward_cost)"
 		Twitch Event: channel.channel_points_custom_reward_redemption.add`
+	OnRedemption Signal.Solo[Redemption] `gd:"on_redemption(redemption)"
+		Twitch Event: channel.channel_points_custom_reward_redemption.add, includes redemption and user IDs, status and redeemed_at`
+	OnRedemptionUpdate Signal.Solo[Redemption] `gd:"on_redemption_update(redemption)"
+		Twitch Event: channel.channel_points_custom_reward_redemption.update, status is either fulfilled or canceled`
+	OnRewardAdd Signal.Solo[Reward] `gd:"on_reward_add(reward)"
+		Twitch Event: channel.channel_points_custom_reward.add`
+	OnRewardUpdate Signal.Solo[Reward] `gd:"on_reward_update(reward)"
+		Twitch Event: channel.channel_points_custom_reward.update`
+	OnRewardRemove Signal.Solo[Reward] `gd:"on_reward_remove(reward)"
+		Twitch Event: channel.channel_points_custom_reward.remove`
+	OnChatNotification Signal.Solo[ChatNotification] `gd:"on_chat_notification(notification)"
+		Twitch Event: channel.chat.notification, only the object matching type is filled`
+	OnChatClear Signal.Solo[int] `gd:"on_chat_clear(unix_cleared_at)"
+		Twitch Event: channel.chat.clear, all chat messages should be removed`
+	OnChatClearUserMessages Signal.Pair[string, string] `gd:"on_chat_clear_user_messages(username,user_id)"
+		Twitch Event: channel.chat.clear_user_messages, all messages of the user should be removed`
+	OnChatMessageDelete Signal.Trio[string, string, string] `gd:"on_chat_message_delete(username,user_id,message_id)"
+		Twitch Event: channel.chat.message_delete`
+	OnAutomaticReward Signal.Quin[string, string, int, string, string] `gd:"on_automatic_reward(type,username,cost,message,emote_id)"
+		Twitch Event: channel.channel_points_automatic_reward_redemption.add, emote_id is set for gigantify_an_emote and can be loaded with GodotTwitchEmoteStore`
 	OnShoutoutCreate Signal.Quad[string, string, string, string] `gd:"on_shoutout_create(username,profile_picture_url,last_stream_game,last_stream_title)"
-		Twitch Event: channel.shoutout.create`
+		Twitch Event: channel.shoutout.create, fetched fields are empty if they could not be fetched in time`
+	OnShoutoutReceived Signal.Quad[string, string, int, int] `gd:"on_shoutout_received(from_username,profile_picture_url,viewer_count,started_at)"
+		Twitch Event: channel.shoutout.receive, started_at is a unix timestamp and profile_picture_url is empty if it could not be fetched`
 	OnDonation Signal.Trio[string, Float.X, string] `gd:"on_donation(username,amount,currency)"
 		Twitch Event: channel.charity_campaign.donate`
+	OnCharityDonation Signal.Solo[CharityDonation] `gd:"on_charity_donation(donation)"
+		Twitch Event: channel.charity_campaign.donate, includes the exact amount in minor units`
+	OnCharityCampaignStart Signal.Solo[CharityCampaign] `gd:"on_charity_campaign_start(campaign)"
+		Twitch Event: channel.charity_campaign.start`
+	OnCharityCampaignProgress Signal.Solo[CharityCampaign] `gd:"on_charity_campaign_progress(campaign)"
+		Twitch Event: channel.charity_campaign.progress`
+	OnCharityCampaignStop Signal.Solo[CharityCampaign] `gd:"on_charity_campaign_stop(campaign)"
+		Twitch Event: channel.charity_campaign.stop`
+	CharityCampaign CharityCampaign `gd:"charity_campaign"
+		State of the current charity campaign, is_active is false if there is none`
+	OnBan Signal.Hexa[string, string, string, string, string, int] `gd:"on_ban(username,user_id,moderator_username,moderator_user_id,reason,duration)"
+		Twitch Event: channel.ban, duration is in seconds and zero for permanent bans`
+	OnUnban Signal.Quad[string, string, string, string] `gd:"on_unban(username,user_id,moderator_username,moderator_user_id)"
+		Twitch Event: channel.unban`
+	OnModeratorAdd Signal.Pair[string, string] `gd:"on_moderator_add(username,user_id)"
+		Twitch Event: channel.moderator.add`
+	OnModeratorRemove Signal.Pair[string, string] `gd:"on_moderator_remove(username,user_id)"
+		Twitch Event: channel.moderator.remove`
+	OnVIPAdd Signal.Pair[string, string] `gd:"on_vip_add(username,user_id)"
+		Twitch Event: channel.vip.add`
+	OnVIPRemove Signal.Pair[string, string] `gd:"on_vip_remove(username,user_id)"
+		Twitch Event: channel.vip.remove`
 	OnPollBegin Signal.Trio[string, int, []Choice] `gd:"on_poll_begin(title,unix_time,choices)"
 		Twitch Event: channel.poll.begin`
 	OnPollProgress Signal.Pair[string, []Choice] `gd:"on_poll_progress(title,choices)"
 		Twitch Event: channel.poll.progress, includes bits_votes, channel_points_votes and votes`
 	OnPollEnd Signal.Pair[string, []Choice] `gd:"on_poll_end(title,choices)"
 		Twitch Event: channel.poll.end, includes bits_votes, channel_points_votes and votes`
+	OnPolls Signal.Solo[[]Poll] `gd:"on_polls(polls)"
+		Result of get_polls, newest first`
+	OnPollError Signal.Pair[string, string] `gd:"on_poll_error(action,message)"
+		Fires when create_poll or end_poll failed, action is create or end`
 	OnPredictionBegin Signal.Trio[string, int, []PredictionOutcome] `gd:"on_prediction_begin(title,unix_lock_time,outcomes)"
 		Twitch Event: channel.prediction.begin`
 	OnPredictionProgress Signal.Pair[string, []PredictionOutcome] `gd:"on_prediction_progress(title,outcomes)"
@@ -61,10 +137,149 @@ type GodotTwitch struct {
 	OnPredictionEnd Signal.Pair[string, []PredictionOutcome] `gd:"on_prediction_end(title,outcomes)"
 		Twitch Event: channel.prediction.end, includes users, channel_points and top_predictors`
 
+	OnStreamOnline Signal.Pair[string, int] `gd:"on_stream_online(stream_type,unix_started_at)"
+		Twitch Event: stream.online`
+	OnStreamOffline Signal.Solo[int] `gd:"on_stream_offline(unix_started_at)"
+		Twitch Event: stream.offline, unix_started_at is the start of the stream that just ended or zero if unknown`
+	OnChannelUpdate Signal.Trio[string, string, string] `gd:"on_channel_update(title,category_id,category_name)"
+		Twitch Event: channel.update`
+	IsLive bool `gd:"is_live"
+		True while the channel is streaming`
+	StreamStartedAt int `gd:"stream_started_at"
+		Unix timestamp of the current stream start or zero if offline`
+	Title string `gd:"title"
+		Current stream title`
+	CategoryName string `gd:"category_name"
+		Name of the current category`
+	CategoryID string `gd:"category_id"
+		ID of the current category`
+
+	FollowerTotal int `gd:"follower_total"
+		Number of followers, loaded on startup and counted up by channel.follow`
+	SubscriberTotal int `gd:"subscriber_total"
+		Number of subscribers on startup`
+	SubPoints int `gd:"sub_points"
+		Sub points on startup, tier 1 counts 1, tier 2 counts 2 and tier 3 counts 6`
+	ActivePoll Poll `gd:"active_poll"
+		The running poll, loaded on startup and kept up to date by poll events. is_active is false if there is none`
+	ActivePrediction Prediction `gd:"active_prediction"
+		The running or locked prediction, loaded on startup and kept up to date by prediction events`
+	ActiveGoals []Goal `gd:"active_goals"
+		Creator goals active on startup`
+	HypeTrain HypeTrain `gd:"hype_train"
+		The latest hype train on startup, is_active is false if it already expired`
+
+	OnFollowEvent Signal.Solo[*TwitchFollowEvent] `gd:"on_follow_event(event)"
+		Same as on_follow with every field of the event`
+	OnSubscriptionEvent Signal.Solo[*TwitchSubscriptionEvent] `gd:"on_subscription_event(event)"
+		Same as on_subscribtion with every field of the event, tier is not shortened for resubs`
+	OnGiftSubEvent Signal.Solo[*TwitchGiftSubEvent] `gd:"on_gift_sub_event(event)"
+		Same as on_sub_gift with every field of the event`
+	OnCheerEvent Signal.Solo[*TwitchCheerEvent] `gd:"on_cheer_event(event)"
+		Same as on_cheer with every field of the event`
+	OnRaidEvent Signal.Solo[*TwitchRaidEvent] `gd:"on_raid_event(event)"
+		Fires for incoming and outgoing raids`
+	OnRedemptionEvent Signal.Solo[*TwitchRedemptionEvent] `gd:"on_redemption_event(event)"
+		Fires for new redemptions and status updates, check type to tell them apart`
+	OnAutomaticRewardEvent Signal.Solo[*TwitchAutomaticRewardEvent] `gd:"on_automatic_reward_event(event)"
+		Same as on_automatic_reward with every field of the event`
+	OnShoutoutEvent Signal.Solo[*TwitchShoutoutEvent] `gd:"on_shoutout_event(event)"
+		Fires for created and received shoutouts`
+	OnDonationEvent Signal.Solo[*TwitchDonationEvent] `gd:"on_donation_event(event)"
+		Same as on_charity_donation as an object`
+	OnBanEvent Signal.Solo[*TwitchBanEvent] `gd:"on_ban_event(event)"
+		Fires for bans and unbans`
+	OnRoleEvent Signal.Solo[*TwitchRoleEvent] `gd:"on_role_event(event)"
+		Fires when a moderator or VIP is added or removed`
+	OnPollEvent Signal.Solo[*TwitchPollEvent] `gd:"on_poll_event(event)"
+		Fires for every poll event`
+	OnPredictionEvent Signal.Solo[*TwitchPredictionEvent] `gd:"on_prediction_event(event)"
+		Fires for every prediction event`
+	OnStreamEvent Signal.Solo[*TwitchStreamEvent] `gd:"on_stream_event(event)"
+		Fires when the stream goes online or offline`
+	OnChannelUpdateEvent Signal.Solo[*TwitchChannelUpdateEvent] `gd:"on_channel_update_event(event)"
+		Same as on_channel_update with every field of the event`
+	OnChatNotificationEvent Signal.Solo[*TwitchChatNotificationEvent] `gd:"on_chat_notification_event(event)"
+		Same as on_chat_notification as an object`
+	OnChatClearEvent Signal.Solo[*TwitchChatClearEvent] `gd:"on_chat_clear_event(event)"
+		Fires when chat, the messages of a user or a single message are cleared`
+	OnRewardEvent Signal.Solo[*TwitchRewardEvent] `gd:"on_reward_event(event)"
+		Fires when a custom reward is added, updated or removed`
+	OnCharityCampaignEvent Signal.Solo[*TwitchCharityCampaignEvent] `gd:"on_charity_campaign_event(event)"
+		Fires when a charity campaign starts, progresses or stops`
+
+	UseAlertQueue bool `gd:"use_alert_queue"
+		If true events are additionally queued as alerts and emitted one at a time through on_alert_start`
+	DefaultAlertDuration Float.X `gd:"default_alert_duration"
+		Seconds an alert is shown if its type has no duration set`
+	OnAlertStart Signal.Pair[*TwitchAlert, Object.Instance] `gd:"on_alert_start(alert,event)"
+		The alert to show now, event is the same object as emitted by the on_*_event signals`
+	OnAlertFinished Signal.Solo[*TwitchAlert] `gd:"on_alert_finished(alert)"
+		Fires when the duration of the running alert ran out or it was skipped`
+
+	Rules []Resource.Instance `gd:"rules"
+		TwitchRule resources checked against every received notification`
+	OnRuleTriggered Signal.Pair[string, map[string]interface{}] `gd:"on_rule_triggered(rule_name,event)"
+		Fires when an event matched a rule, event is the payload as sent by twitch`
+	OnRuleAction Signal.Trio[string, string, map[string]interface{}] `gd:"on_rule_action(action,rule_name,event)"
+		Same as on_rule_triggered for rules with an action`
+
+	RecordingPath string `gd:"recording_path"
+		If set every websocket frame is appended to this file with the time it was received, user:// paths are supported`
+	OnPlaybackFinished Signal.Solo[string] `gd:"on_playback_finished(path)"
+		Fires when start_playback sent the last frame or was stopped`
+
+	OnUserInfo Signal.Pair[string, UserInfo] `gd:"on_user_info(login_or_id,user_info)"
+		Result of get_user_info, user_info.id is empty if the user was not found`
+	OnEventHistory Signal.Pair[string, []HistoryEntry] `gd:"on_event_history(type,entries)"
+		Result of get_event_history, entries are newest first`
+
 	twitchClient *helix.Client
+	journal      *lib.Journal
+	sessionStats *lib.SessionStats
+	// session stats are saved at most every sessionStatsSaveInterval
+	sessionStatsDirty   bool
+	sessionStatsSavedAt time.Time
+	recorder            *lib.Recorder
+	player              *lib.Player
+	userCache           *lib.UserCache
+	enrichmentLookups   chan struct{}
+	// set by the Ready goroutine once the token is valid and read by api goroutines
+	broadcasterLock   sync.Mutex
+	broadcasterUserID string
+
+	customSubscriptionLock sync.Mutex
+	customSubscriptions    []customSubscription
+	wsSessionID            string
 
 	eventProcessLock  sync.Mutex
-	eventProcessQueue []lib.TwitchMessage
+	eventProcessQueue []*enrichedMessage
+
+	rewardCatalog map[string]Reward
+
+	bitsLeaderboardPeriod string
+	bitsLeaderboardCount  int
+	// running get_bits_leaderboard requests, refreshes on cheer wait for them
+	bitsLeaderboardRequests       int
+	bitsLeaderboardRefreshPending bool
+
+	giftBombs lib.GiftBombs[*TwitchGiftSubEvent]
+
+	// properties set by events are not replaced by the api bootstrap
+	latestFollowerFromEvent   bool
+	latestSubscriberFromEvent bool
+	streamStateFromEvent      bool
+	channelInfoFromEvent      bool
+
+	ruleStates   map[*TwitchRule]*ruleState
+	rulePatterns map[string]*regexp.Regexp
+
+	alertSettings         map[string]alertSetting
+	alertQueue            []*queuedAlert
+	alertSequence         int
+	alertsPaused          bool
+	currentAlert          *queuedAlert
+	currentAlertRemaining Float.X
 
 	apiInfoResponseLock  sync.Mutex
 	apiInfoResponseQueue []interface{}
@@ -72,6 +287,12 @@ type GodotTwitch struct {
 	hasNewToken bool
 }
 
+type customSubscription struct {
+	eventType string
+	version   string
+	condition map[string]string
+}
+
 type Choice struct {
 	ID                 string `gd:"id"`
 	Title              string `gd:"title"`
@@ -80,6 +301,112 @@ type Choice struct {
 	Votes              int    `gd:"votes"`
 }
 
+type UserInfo struct {
+	ID                string `gd:"id"`
+	Login             string `gd:"login"`
+	DisplayName       string `gd:"display_name"`
+	ProfilePictureURL string `gd:"profile_picture_url"`
+	BroadcasterType   string `gd:"broadcaster_type"`
+	Description       string `gd:"description"`
+	UnixCreatedAt     int    `gd:"unix_created_at"`
+}
+
+type Redemption struct {
+	ID             string `gd:"id"`
+	UserID         string `gd:"user_id"`
+	UserLogin      string `gd:"user_login"`
+	UserName       string `gd:"user_name"`
+	UserInput      string `gd:"user_input"`
+	Status         string `gd:"status"`
+	UnixRedeemedAt int    `gd:"unix_redeemed_at"`
+	RewardID       string `gd:"reward_id"`
+	RewardTitle    string `gd:"reward_title"`
+	RewardPrompt   string `gd:"reward_prompt"`
+	RewardCost     int    `gd:"reward_cost"`
+}
+
+type Reward struct {
+	ID                  string `gd:"id"`
+	Title               string `gd:"title"`
+	Prompt              string `gd:"prompt"`
+	Cost                int    `gd:"cost"`
+	BackgroundColor     string `gd:"background_color"`
+	IsEnabled           bool   `gd:"is_enabled"`
+	IsPaused            bool   `gd:"is_paused"`
+	IsInStock           bool   `gd:"is_in_stock"`
+	IsUserInputRequired bool   `gd:"is_user_input_required"`
+}
+
+// CharityCampaign values are in minor units, e.g. cents, with decimal_places telling where the decimal
+// point goes. current_amount and target_amount are the same values converted for display.
+type CharityCampaign struct {
+	ID                 string  `gd:"id"`
+	IsActive           bool    `gd:"is_active"`
+	CharityName        string  `gd:"charity_name"`
+	CharityDescription string  `gd:"charity_description"`
+	CharityLogoURL     string  `gd:"charity_logo_url"`
+	CharityWebsite     string  `gd:"charity_website"`
+	CurrentValue       int     `gd:"current_value"`
+	TargetValue        int     `gd:"target_value"`
+	DecimalPlaces      int     `gd:"decimal_places"`
+	Currency           string  `gd:"currency"`
+	CurrentAmount      Float.X `gd:"current_amount"`
+	TargetAmount       Float.X `gd:"target_amount"`
+}
+
+type CharityDonation struct {
+	ID            string  `gd:"id"`
+	CampaignID    string  `gd:"campaign_id"`
+	UserID        string  `gd:"user_id"`
+	UserLogin     string  `gd:"user_login"`
+	UserName      string  `gd:"user_name"`
+	CharityName   string  `gd:"charity_name"`
+	Value         int     `gd:"value"`
+	DecimalPlaces int     `gd:"decimal_places"`
+	Currency      string  `gd:"currency"`
+	Amount        Float.X `gd:"amount"`
+}
+
+type Poll struct {
+	ID            string   `gd:"id"`
+	IsActive      bool     `gd:"is_active"`
+	Title         string   `gd:"title"`
+	Status        string   `gd:"status"`
+	Choices       []Choice `gd:"choices"`
+	UnixStartedAt int      `gd:"unix_started_at"`
+	UnixEndsAt    int      `gd:"unix_ends_at"`
+}
+
+type Prediction struct {
+	ID            string              `gd:"id"`
+	IsActive      bool                `gd:"is_active"`
+	Title         string              `gd:"title"`
+	Status        string              `gd:"status"`
+	Outcomes      []PredictionOutcome `gd:"outcomes"`
+	UnixStartedAt int                 `gd:"unix_started_at"`
+	UnixLocksAt   int                 `gd:"unix_locks_at"`
+}
+
+type Goal struct {
+	ID            string `gd:"id"`
+	Type          string `gd:"type"`
+	Description   string `gd:"description"`
+	CurrentAmount int    `gd:"current_amount"`
+	TargetAmount  int    `gd:"target_amount"`
+	UnixCreatedAt int    `gd:"unix_created_at"`
+}
+
+type HypeTrain struct {
+	ID                string `gd:"id"`
+	IsActive          bool   `gd:"is_active"`
+	Level             int    `gd:"level"`
+	Total             int    `gd:"total"`
+	Goal              int    `gd:"goal"`
+	UnixStartedAt     int    `gd:"unix_started_at"`
+	UnixExpiresAt     int    `gd:"unix_expires_at"`
+	UnixCooldownEndAt int    `gd:"unix_cooldown_end_at"`
+}
+
 type PredictionOutcome struct {
 	ID            string         `gd:"id"`
 	Title         string         `gd:"title"`
@@ -99,8 +426,70 @@ type TopPredictor struct {
 type (
 	LatestFollowerUpdate struct {
 		Username string
+		Total    int
 	}
 	LatestSubscriberUpdate struct {
 		Username string
+		Total    int
+		Points   int
+	}
+	RewardCatalogUpdate struct {
+		Rewards []Reward
+	}
+	CharityCampaignUpdate struct {
+		Campaign CharityCampaign
+	}
+	StreamStateUpdate struct {
+		IsLive    bool
+		StartedAt int
+	}
+	ActivePollUpdate struct {
+		Poll Poll
+	}
+	ActivePredictionUpdate struct {
+		Prediction Prediction
+	}
+	GoalsUpdate struct {
+		Goals []Goal
+	}
+	HypeTrainUpdate struct {
+		HypeTrain HypeTrain
+	}
+	BitsLeaderboardUpdate struct {
+		Period  string
+		Entries []BitsLeaderboardEntry
+		Err     error
+	}
+	PollsResponse struct {
+		Polls []Poll
+	}
+	EventHistoryResponse struct {
+		Type    string
+		Entries []HistoryEntry
+	}
+	JournalRestoreUpdate struct {
+		LatestFollower   string
+		LatestSubscriber string
+	}
+	RaidResult struct {
+		Action string
+		Target string
+		Error  string
+	}
+	PollError struct {
+		Action  string
+		Message string
+	}
+	PlaybackFinished struct {
+		Path string
+	}
+	UserInfoResponse struct {
+		Query string
+		Info  UserInfo
+	}
+	ChannelInfoUpdate struct {
+		Title        string
+		CategoryID   string
+		CategoryName string
 	}
 )
@@ -1,6 +1,7 @@
 package lib
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -731,7 +732,7 @@ var EventItems = []list.Item{
 																	"user_id": "1236",
 																	"channel_points_won": 5000,
 																	"channel_points_used": 100
-															},
+															}
 													]
 											},
 											{
@@ -744,14 +745,14 @@ var EventItems = []list.Item{
 															{
 																	"user_name": "Cooler_User",
 																	"user_login": "cooler_user",
-																	"user_id": 12345,
+																	"user_id": "12345",
 																	"channel_points_won": null,
 																	"channel_points_used": 100
 															},
 															{
 																	"user_name": "Elite_User",
 																	"user_login": "elite_user",
-																	"user_id": 1337,
+																	"user_id": "1337",
 																	"channel_points_won": null,
 																	"channel_points_used": 100
 															}
@@ -770,4 +771,456 @@ var EventItems = []list.Item{
 			)
 		},
 	},
+	EventItem{
+		title:       "Stream online",
+		twitchEvent: helix.EventSubTypeStreamOnline,
+		description: "Test the stream going live",
+		MakeForm: func() *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewSelect[string]().Key("stream_type").Title("Stream type").Options(
+						huh.NewOption("Live", "live"),
+						huh.NewOption("Playlist", "playlist"),
+						huh.NewOption("Watch party", "watch_party"),
+						huh.NewOption("Premiere", "premiere"),
+						huh.NewOption("Rerun", "rerun"),
+					),
+				),
+			)
+		},
+		MakePayload: func(f *huh.Form) string {
+			return fmt.Sprintf(
+				`{
+					"metadata": {
+						"message_id": "befa7b53-d79d-478f-86b9-120f112b044e",
+						"message_type": "notification",
+						"message_timestamp": "2022-11-16T10:11:12.464757833Z",
+						"subscription_type": "stream.online",
+						"subscription_version": "1"
+					},
+					"payload": {
+							"subscription": {
+									"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+									"type": "stream.online",
+									"version": "1",
+									"status": "enabled",
+									"cost": 0,
+									"condition": {
+											"broadcaster_user_id": "1337"
+									},
+									"transport": {
+											"method": "webhook",
+											"callback": "https://example.com/webhooks/callback"
+									},
+									"created_at": "2019-11-16T10:11:12.634234626Z"
+							},
+							"event": {
+									"id": "9001",
+									"broadcaster_user_id": "1337",
+									"broadcaster_user_login": "cool_user",
+									"broadcaster_user_name": "Cool_User",
+									"type": "%s",
+									"started_at": "%s"
+							}
+					}
+				}`,
+				f.GetString("stream_type"),
+				time.Now().Format(time.RFC3339),
+			)
+		},
+	},
+	EventItem{
+		title:       "Stream offline",
+		twitchEvent: helix.EventSubTypeStreamOffline,
+		description: "Test the stream ending",
+		MakeForm: func() *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().Key("confirm").Title("Send stream offline"),
+				),
+			)
+		},
+		MakePayload: func(f *huh.Form) string {
+			return `{
+				"metadata": {
+					"message_id": "befa7b53-d79d-478f-86b9-120f112b044e",
+					"message_type": "notification",
+					"message_timestamp": "2022-11-16T10:11:12.464757833Z",
+					"subscription_type": "stream.offline",
+					"subscription_version": "1"
+				},
+				"payload": {
+						"subscription": {
+								"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+								"type": "stream.offline",
+								"version": "1",
+								"status": "enabled",
+								"cost": 0,
+								"condition": {
+										"broadcaster_user_id": "1337"
+								},
+								"transport": {
+										"method": "webhook",
+										"callback": "https://example.com/webhooks/callback"
+								},
+								"created_at": "2019-11-16T10:11:12.634234626Z"
+						},
+						"event": {
+								"broadcaster_user_id": "1337",
+								"broadcaster_user_login": "cool_user",
+								"broadcaster_user_name": "Cool_User"
+						}
+				}
+			}`
+		},
+	},
+	EventItem{
+		title:       "Channel update",
+		twitchEvent: helix.EventSubTypeChannelUpdate,
+		description: "Test a title or category change",
+		MakeForm: func() *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().Key("title").Title("Stream title"),
+					huh.NewInput().Key("category_id").Title("Category ID"),
+					huh.NewInput().Key("category_name").Title("Category name"),
+				),
+			)
+		},
+		MakePayload: func(f *huh.Form) string {
+			return fmt.Sprintf(
+				`{
+					"metadata": {
+						"message_id": "befa7b53-d79d-478f-86b9-120f112b044e",
+						"message_type": "notification",
+						"message_timestamp": "2022-11-16T10:11:12.464757833Z",
+						"subscription_type": "channel.update",
+						"subscription_version": "2"
+					},
+					"payload": {
+							"subscription": {
+									"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+									"type": "channel.update",
+									"version": "2",
+									"status": "enabled",
+									"cost": 0,
+									"condition": {
+											"broadcaster_user_id": "1337"
+									},
+									"transport": {
+											"method": "webhook",
+											"callback": "https://example.com/webhooks/callback"
+									},
+									"created_at": "2019-11-16T10:11:12.634234626Z"
+							},
+							"event": {
+									"broadcaster_user_id": "1337",
+									"broadcaster_user_login": "cool_user",
+									"broadcaster_user_name": "Cool_User",
+									"title": "%s",
+									"language": "en",
+									"category_id": "%s",
+									"category_name": "%s",
+									"content_classification_labels": []
+							}
+					}
+				}`,
+				f.GetString("title"),
+				f.GetString("category_id"),
+				f.GetString("category_name"),
+			)
+		},
+	},
+	EventItem{
+		title:       "Chat notification resub",
+		twitchEvent: helix.EventSubTypeChannelChatNotification,
+		description: "Test a resub chat notification with message",
+		MakeForm: func() *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().Key("username").Title("Username").Prompt("?"),
+					huh.NewInput().Key("message").Title("Resub message"),
+					huh.NewInput().Key("months").Title("Cumulative months"),
+				),
+			)
+		},
+		MakePayload: func(f *huh.Form) string {
+			months, _ := strconv.Atoi(f.GetString("months"))
+			return fmt.Sprintf(
+				`{
+					"metadata": {
+						"message_id": "befa7b53-d79d-478f-86b9-120f112b044e",
+						"message_type": "notification",
+						"message_timestamp": "2022-11-16T10:11:12.464757833Z",
+						"subscription_type": "channel.chat.notification",
+						"subscription_version": "1"
+					},
+					"payload": {
+							"subscription": {
+									"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+									"type": "channel.chat.notification",
+									"version": "1",
+									"status": "enabled",
+									"cost": 0,
+									"condition": {
+											"broadcaster_user_id": "1337",
+											"user_id": "1337"
+									},
+									"transport": {
+											"method": "webhook",
+											"callback": "https://example.com/webhooks/callback"
+									},
+									"created_at": "2019-11-16T10:11:12.634234626Z"
+							},
+							"event": {
+									"broadcaster_user_id": "1337",
+									"broadcaster_user_login": "cool_user",
+									"broadcaster_user_name": "Cool_User",
+									"chatter_user_id": "9001",
+									"chatter_user_login": "%s",
+									"chatter_user_name": "%s",
+									"chatter_is_anonymous": false,
+									"color": "#9146FF",
+									"badges": [],
+									"system_message": "%s subscribed at Tier 1. They've subscribed for %d months!",
+									"message_id": "d62235c8-47ff-a4f4-84e8-5a29a65a9c03",
+									"message": {
+											"text": "%s",
+											"fragments": [
+													{
+															"type": "text",
+															"text": "%s",
+															"cheermote": null,
+															"emote": null,
+															"mention": null
+													}
+											]
+									},
+									"notice_type": "resub",
+									"sub": null,
+									"resub": {
+											"cumulative_months": %d,
+											"duration_months": 1,
+											"streak_months": null,
+											"sub_tier": "1000",
+											"is_prime": false,
+											"is_gift": false,
+											"gifter_is_anonymous": null,
+											"gifter_user_id": null,
+											"gifter_user_name": null,
+											"gifter_user_login": null
+									},
+									"sub_gift": null,
+									"community_sub_gift": null,
+									"gift_paid_upgrade": null,
+									"prime_paid_upgrade": null,
+									"pay_it_forward": null,
+									"raid": null,
+									"unraid": null,
+									"announcement": null,
+									"bits_badge_tier": null,
+									"charity_donation": null
+							}
+					}
+				}`,
+				strings.ToLower(f.GetString("username")),
+				f.GetString("username"),
+				f.GetString("username"),
+				months,
+				f.GetString("message"),
+				f.GetString("message"),
+				months,
+			)
+		},
+	},
+	EventItem{
+		title:       "Shoutout received",
+		twitchEvent: helix.EventSubShoutoutReceive,
+		description: "Test an incoming shoutout",
+		MakeForm: func() *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().
+						Key("streamer_id").
+						Title("Streamer user ID").
+						Description("This must be real so we can fetch some more user info"),
+					huh.NewInput().Key("streamer").Title("Streamer username").Prompt("?"),
+					huh.NewInput().Key("viewer_count").Title("Viewer count"),
+				),
+			)
+		},
+		MakePayload: func(f *huh.Form) string {
+			viewers, _ := strconv.Atoi(f.GetString("viewer_count"))
+			return fmt.Sprintf(
+				`{
+					"metadata": {
+						"message_id": "befa7b53-d79d-478f-86b9-120f112b044e",
+						"message_type": "notification",
+						"message_timestamp": "2022-11-16T10:11:12.464757833Z",
+						"subscription_type": "channel.shoutout.receive",
+						"subscription_version": "1"
+					},
+					"payload": {
+							"subscription": {
+									"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+									"type": "channel.shoutout.receive",
+									"version": "1",
+									"status": "enabled",
+									"cost": 0,
+									"condition": {
+											"broadcaster_user_id": "1337",
+											"moderator_user_id": "1337"
+									},
+									"transport": {
+											"method": "webhook",
+											"callback": "https://example.com/webhooks/callback"
+									},
+									"created_at": "2019-11-16T10:11:12.634234626Z"
+							},
+							"event": {
+									"broadcaster_user_id": "1337",
+									"broadcaster_user_login": "cool_user",
+									"broadcaster_user_name": "Cool_User",
+									"from_broadcaster_user_id": "%s",
+									"from_broadcaster_user_login": "%s",
+									"from_broadcaster_user_name": "%s",
+									"viewer_count": %d,
+									"started_at": "%s"
+							}
+					}
+				}`,
+				f.GetString("streamer_id"),
+				strings.ToLower(f.GetString("streamer")),
+				f.GetString("streamer"),
+				viewers,
+				time.Now().Format(time.RFC3339),
+			)
+		},
+	},
+	EventItem{
+		title:       "Ban",
+		twitchEvent: helix.EventSubTypeChannelBan,
+		description: "Test a ban or timeout",
+		MakeForm: func() *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().Key("username").Title("Username").Prompt("?"),
+					huh.NewInput().Key("reason").Title("Reason"),
+					huh.NewInput().Key("duration").Title("Timeout seconds").Description("Leave empty for a permanent ban"),
+				),
+			)
+		},
+		MakePayload: func(f *huh.Form) string {
+			duration, _ := strconv.Atoi(f.GetString("duration"))
+			endsAt := "null"
+			if duration > 0 {
+				endsAt = fmt.Sprintf(`"%s"`, time.Now().Add(time.Duration(duration)*time.Second).Format(time.RFC3339))
+			}
+			return fmt.Sprintf(
+				`{
+					"metadata": {
+						"message_id": "befa7b53-d79d-478f-86b9-120f112b044e",
+						"message_type": "notification",
+						"message_timestamp": "2022-11-16T10:11:12.464757833Z",
+						"subscription_type": "channel.ban",
+						"subscription_version": "1"
+					},
+					"payload": {
+							"subscription": {
+									"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+									"type": "channel.ban",
+									"version": "1",
+									"status": "enabled",
+									"cost": 0,
+									"condition": {
+											"broadcaster_user_id": "1337"
+									},
+									"transport": {
+											"method": "webhook",
+											"callback": "https://example.com/webhooks/callback"
+									},
+									"created_at": "2019-11-16T10:11:12.634234626Z"
+							},
+							"event": {
+									"user_id": "1234",
+									"user_login": "%s",
+									"user_name": "%s",
+									"broadcaster_user_id": "1337",
+									"broadcaster_user_login": "cooler_user",
+									"broadcaster_user_name": "Cooler_User",
+									"moderator_user_id": "1339",
+									"moderator_user_login": "mod_user",
+									"moderator_user_name": "Mod_User",
+									"reason": "%s",
+									"banned_at": "%s",
+									"ends_at": %s,
+									"is_permanent": %t
+							}
+					}
+				}`,
+				strings.ToLower(f.GetString("username")),
+				f.GetString("username"),
+				f.GetString("reason"),
+				time.Now().Format(time.RFC3339),
+				endsAt,
+				duration <= 0,
+			)
+		},
+	},
+	EventItem{
+		title:       "Cheer",
+		twitchEvent: helix.EventSubTypeChannelCheer,
+		description: "Test bits being cheered",
+		MakeForm: func() *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().Key("username").Title("Username").Prompt("?"),
+					huh.NewInput().Key("bits").Title("Bits").Prompt("?"),
+					huh.NewInput().Key("message").Title("Message").Prompt("?"),
+				),
+			)
+		},
+		MakePayload: func(f *huh.Form) string {
+			bits, _ := strconv.Atoi(f.GetString("bits"))
+			message, _ := json.Marshal(f.GetString("message"))
+
+			return strings.ReplaceAll(fmt.Sprintf(`{
+	"metadata": {
+		"message_id": "6a4e3a84-3b1e-4a36-9e2f-9d2f4f8c9b31",
+		"message_type": "notification",
+		"message_timestamp": "2022-11-16T10:11:12.464757833Z",
+		"subscription_type": "channel.cheer",
+		"subscription_version": "1"
+	},
+	"payload": {
+		"subscription": {
+				"id": "f1c2a387-161a-49f9-a165-0f21d7a4e1c4",
+				"type": "channel.cheer",
+				"version": "1",
+				"status": "enabled",
+				"cost": 0,
+				"condition": {
+					"broadcaster_user_id": "1337"
+				},
+				"transport": {
+						"method": "webhook",
+						"callback": "https://example.com/webhooks/callback"
+				},
+				"created_at": "2019-11-16T10:11:12.634234626Z"
+		},
+		"event": {
+				"is_anonymous": false,
+				"user_id": "1234",
+				"user_login": "%s",
+				"user_name": "%s",
+				"broadcaster_user_id": "1337",
+				"broadcaster_user_login": "cooler_user",
+				"broadcaster_user_name": "Cooler_User",
+				"message": %s,
+				"bits": %d
+		}
+	}
+}`, strings.ToLower(f.GetString("username")), f.GetString("username"), message, bits), "\n", "")
+		},
+	},
 }
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"log"
+	mainlib "main/lib"
 	"main/util/ws_mockserver/lib"
 	"net/http"
 	"os"
@@ -195,7 +198,44 @@ func newItemDelegate() list.DefaultDelegate {
 	return d
 }
 
+// replay sends the notifications of a recording made with recording_path instead of showing the
+// event list. With a speed of zero every press of enter sends the next notification.
+func replay(path string, speed float64, eventChannel chan<- string) {
+	frames, err := mainlib.ReadRecording(path)
+	if err != nil {
+		fmt.Println("Error reading recording:", err)
+		os.Exit(1)
+	}
+
+	player := mainlib.NewPlayer(frames, speed)
+	if speed <= 0 {
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				player.Step()
+			}
+		}()
+	}
+
+	fmt.Printf("Replaying %d frames of %s\n", len(frames), path)
+	_ = player.Play(func(frame mainlib.RecordedFrame) error {
+		msg, err := mainlib.DecodeFrame(frame)
+		if err != nil || msg.Metadata.Type != "notification" {
+			return nil
+		}
+
+		eventChannel <- string(frame.Frame)
+		fmt.Printf("Sent %s\n", msg.Payload.Subscription.Type)
+		return nil
+	})
+	fmt.Println("Replay finished, press ctrl+c to quit")
+}
+
 func main() {
+	replayPath := flag.String("replay", "", "recording to replay instead of showing the event list")
+	replaySpeed := flag.Float64("speed", 1, "replay speed, 0 waits for enter before every event")
+	flag.Parse()
+
 	eventChannel := make(chan string)
 	statusChan := make(chan string, 1)
 	m := newModel(eventChannel, statusChan)
@@ -253,6 +293,17 @@ func main() {
 		}
 	}()
 
+	if *replayPath != "" {
+		// wait for the node to connect
+		fmt.Println(<-statusChan)
+		go func() {
+			for range statusChan {
+			}
+		}()
+		replay(*replayPath, *replaySpeed, eventChannel)
+		select {}
+	}
+
 	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)